@@ -25,7 +25,12 @@ THE SOFTWARE.
 package db
 
 import (
+	"os"
+	"strings"
+
 	"github.com/J-Siu/go-auto-docker/cmd/root"
+	"github.com/J-Siu/go-auto-docker/global"
+	"github.com/J-Siu/go-helper/v2/ezlog"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +39,16 @@ var dbCmd = &cobra.Command{
 	Use:     "db",
 	Aliases: []string{"d"},
 	Short:   "DB commands",
+	// Database of --distro, check and update get database of each project from registry instead
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		prefix := "db"
+		root.RootCmd.PersistentPreRun(cmd, args)
+		global.Db = global.DbRegistry.Get(global.Flag.Distro)
+		if global.Db == nil {
+			ezlog.Err().N(prefix).M(global.Flag.Distro + " not supported, supported: " + strings.Join(global.DbRegistry.Distros(), ", ")).Out()
+			os.Exit(1)
+		}
+	},
 }
 
 func init() {
@@ -0,0 +1,64 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/J-Siu/go-auto-docker/global"
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/spf13/cobra"
+)
+
+// branchesCmd represents the dbBranches command
+var branchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "List available branches, from release metadata or mirror listing",
+	Run: func(cmd *cobra.Command, args []string) {
+		if global.Db.Err() == nil {
+			var (
+				latest     = global.Db.BranchLatest()
+				tab_Writer = tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+			)
+			fmt.Fprintln(tab_Writer, strings.Join([]string{"Branch", "EOL", "Latest"}, "\t"))
+			for _, branch := range global.Db.Branches() {
+				isLatest := ""
+				if branch == latest {
+					isLatest = "Yes"
+				}
+				fmt.Fprintln(tab_Writer, strings.Join([]string{branch, global.Db.BranchEol(branch, ""), isLatest}, "\t"))
+			}
+			tab_Writer.Flush()
+		}
+		errs.Queue("", global.Db.Err())
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(branchesCmd)
+}
@@ -0,0 +1,69 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/J-Siu/go-auto-docker/global"
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/spf13/cobra"
+)
+
+// depsCmd represents the dbDeps command
+var depsCmd = &cobra.Command{
+	Use:     "deps <pkg>",
+	Aliases: []string{"dep"},
+	Short:   "Show package dependencies",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if global.Db.Err() == nil {
+			var (
+				strArrArr  *[]*[]string
+				tab_Writer = tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+			)
+			for _, pkg := range args {
+				strArrArr = global.Db.Deps(pkg, global.FlagDbDeps.Branch, global.FlagDbDeps.Repo, global.FlagDbDeps.Arch, global.FlagDbDeps.Recursive)
+				for _, strArr := range *strArrArr {
+					fmt.Fprintln(tab_Writer, strings.Join(*strArr, "\t"))
+				}
+			}
+			tab_Writer.Flush()
+		}
+		errs.Queue("", global.Db.Err())
+	},
+}
+
+func init() {
+	cmd := depsCmd
+	dbCmd.AddCommand(cmd)
+	cmd.Flags().StringVarP(&global.FlagDbDeps.Arch, "arch", "a", "x86_64", "architecture")
+	cmd.Flags().StringVarP(&global.FlagDbDeps.Branch, "branch", "b", "latest-stable", "branch")
+	cmd.Flags().StringVarP(&global.FlagDbDeps.Repo, "repo", "r", "", "repository, default all")
+	cmd.Flags().BoolVarP(&global.FlagDbDeps.Recursive, "recursive", "R", false, "walk whole dependency tree")
+}
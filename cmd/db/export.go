@@ -0,0 +1,79 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/J-Siu/go-auto-docker/db"
+	"github.com/J-Siu/go-auto-docker/global"
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the dbExport command
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Aliases: []string{"e"},
+	Short:   "Export packages as json, csv or ndjson",
+	Run: func(cmd *cobra.Command, args []string) {
+		prefix := "export"
+		var (
+			err  error
+			file *os.File
+			w    io.Writer = os.Stdout
+		)
+		if global.Db.Err() == nil && global.FlagDbExport.Output != "" {
+			file, err = os.Create(global.FlagDbExport.Output)
+			w = file
+		}
+		if global.Db.Err() == nil && err == nil {
+			buf := bufio.NewWriter(w)
+			count := global.Db.Export(buf, global.FlagDbExport.Format, global.FlagDbExport.Branch, global.FlagDbExport.Repo, global.FlagDbExport.Arch)
+			err = buf.Flush()
+			ezlog.Debug().N(prefix).N("Rows").M(count).Out()
+		}
+		if file != nil {
+			if errClose := file.Close(); err == nil {
+				err = errClose
+			}
+		}
+		errs.Queue(prefix, err)
+		errs.Queue("", global.Db.Err())
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&global.FlagDbExport.Format, "format", "f", "json", "output format: "+strings.Join(db.ExportFormats, ", "))
+	exportCmd.Flags().StringVarP(&global.FlagDbExport.Branch, "branch", "b", "", "branch, default all")
+	exportCmd.Flags().StringVarP(&global.FlagDbExport.Repo, "repo", "r", "", "repository, default all")
+	exportCmd.Flags().StringVarP(&global.FlagDbExport.Arch, "arch", "a", "", "architecture, default all")
+	exportCmd.Flags().StringVarP(&global.FlagDbExport.Output, "output", "o", "", "output file, default stdout")
+}
@@ -4,11 +4,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package db
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/J-Siu/go-auto-docker/db"
 	"github.com/J-Siu/go-auto-docker/global"
 	"github.com/J-Siu/go-helper/v2/errs"
 	"github.com/spf13/cobra"
@@ -16,22 +18,65 @@ import (
 
 // searchCmd represents the dbSearch command
 var searchCmd = &cobra.Command{
-	Use:     "search",
+	Use:     "search <pkg>",
 	Aliases: []string{"s"},
 	Short:   "Search database",
+	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		prefix := "search"
 		if global.Db.Err() == nil {
 			var (
+				columns    = db.SearchColumns
+				option     = &global.FlagDbSearch.TypeDbSearchOption
+				results    []map[string]string
 				strArrArr  *[]*[]string
 				tab_Writer = tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
 			)
+			if global.FlagDbSearch.Provides && !global.FlagDbSearch.Text {
+				columns = db.SearchProvidesColumns
+			}
 			for _, pkg := range args {
-				strArrArr = global.Db.Search(pkg, global.FlagDbSearch.Exact)
+				if global.FlagDbSearch.Text {
+					strArrArr = global.Db.SearchText(pkg, option)
+				} else if global.FlagDbSearch.Provides {
+					strArrArr = global.Db.SearchProvides(pkg, option)
+				} else {
+					strArrArr = global.Db.Search(pkg, option)
+				}
 				for _, strArr := range *strArrArr {
-					fmt.Fprintln(tab_Writer, strings.Join(*strArr, "\t"))
+					switch global.FlagDbSearch.Format {
+					case "json":
+						result := map[string]string{}
+						for i, column := range columns {
+							result[column] = (*strArr)[i]
+						}
+						results = append(results, result)
+					default:
+						fmt.Fprintln(tab_Writer, strings.Join(*strArr, "\t"))
+					}
+				}
+				// Did you mean, to stderr, stdout stay parsable
+				if len(*strArrArr) == 0 && !global.FlagDbSearch.Provides && !global.FlagDbSearch.Text && global.Db.Err() == nil {
+					if names := global.Db.Suggest(pkg, option); len(names) > 0 {
+						fmt.Fprintln(os.Stderr, pkg+" not found, did you mean: "+strings.Join(names, ", "))
+					}
+				}
+			}
+			switch global.FlagDbSearch.Format {
+			case "json":
+				if results == nil {
+					results = []map[string]string{}
+				}
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err == nil {
+					fmt.Println(string(data))
 				}
+				errs.Queue(prefix, err)
+			case "table":
+				tab_Writer.Flush()
+			default:
+				errs.Queue(prefix, fmt.Errorf("unknown format %s, supported: table, json", global.FlagDbSearch.Format))
 			}
-			tab_Writer.Flush()
 		}
 		errs.Queue("", global.Db.Err())
 	},
@@ -40,4 +85,12 @@ var searchCmd = &cobra.Command{
 func init() {
 	dbCmd.AddCommand(searchCmd)
 	searchCmd.Flags().BoolVarP(&global.FlagDbSearch.Exact, "exact", "e", false, "search exact word")
+	searchCmd.Flags().BoolVarP(&global.FlagDbSearch.Provides, "provides", "p", false, "search by provides, eg. cmd:sh, so:libz.so.1")
+	searchCmd.Flags().BoolVarP(&global.FlagDbSearch.Text, "text", "t", false, "full-text search in name, description and URL, ranked by relevance, eg. \"http proxy\"")
+	searchCmd.Flags().BoolVarP(&global.FlagDbSearch.Regex, "regex", "x", false, "search by regular expression, eg. ^py3-.*-doc$")
+	searchCmd.Flags().BoolVarP(&global.FlagDbSearch.Latest, "latest", "l", false, "newest version per package and branch only")
+	searchCmd.Flags().StringVarP(&global.FlagDbSearch.Branch, "branch", "b", "", "branch, default all")
+	searchCmd.Flags().StringVarP(&global.FlagDbSearch.Repo, "repo", "r", "", "repository, default all")
+	searchCmd.Flags().StringVarP(&global.FlagDbSearch.Arch, "arch", "a", "", "architecture, default all")
+	searchCmd.Flags().StringVarP(&global.FlagDbSearch.Format, "format", "f", "table", "output format: table, json")
 }
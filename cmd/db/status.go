@@ -0,0 +1,60 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/J-Siu/go-auto-docker/global"
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the dbStatus command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show age of every index, stale if older than DbMaxAge",
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			strArrArr  *[]*[]string
+			tab_Writer = tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+		)
+		strArrArr = global.Db.Status(time.Duration(global.Conf.DbMaxAge) * time.Hour)
+		fmt.Fprintln(tab_Writer, strings.Join([]string{"Branch", "Repo", "Arch", "Rows", "FetchedAt", "Age", "Stale"}, "\t"))
+		for _, strArr := range *strArrArr {
+			fmt.Fprintln(tab_Writer, strings.Join(*strArr, "\t"))
+		}
+		tab_Writer.Flush()
+		errs.Queue("", global.Db.Err())
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(statusCmd)
+}
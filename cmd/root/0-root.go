@@ -26,6 +26,7 @@ package root
 
 import (
 	"os"
+	"time"
 
 	"github.com/J-Siu/go-auto-docker/db"
 	"github.com/J-Siu/go-auto-docker/global"
@@ -41,7 +42,6 @@ var RootCmd = &cobra.Command{
 	Short:   "Mass updating single package Docker project base on Alpine Linux packages.",
 	Long:    `Automate update for README.md change log, apply tag according to package version. Also handle test build, git commit.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		prefix := "root"
 		ezlog.SetLogLevel(ezlog.ERR)
 		if global.Flag.Debug {
 			ezlog.SetLogLevel(ezlog.DEBUG)
@@ -49,22 +49,133 @@ var RootCmd = &cobra.Command{
 		ezlog.Debug().N("Version").M(global.Version).Ln("Flag").Lm(&global.Flag).Out()
 		global.Conf.New()
 
-		global.Db = new(db.TypeDbAlpine).
-			New(&global.Conf.DirCache, &global.Conf.DirDB, &global.Conf.AlpineBranch).
-			Connect()
-		if global.Flag.UpdateDb {
-			ezlog.Log().M("db update").Out()
-			global.Db.Update()
+		dbRegister()
+		if cmd == checkCmd || cmd == updateCmd {
+			global.DbRegistry.MaxAge = time.Duration(global.Conf.DbMaxAge) * time.Hour
+			global.DbRegistry.Offline = global.Flag.Offline
+			dbReference(args)
 		}
-		errs.Queue(prefix, global.Db.Err())
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		if errs.NotEmpty() {
 			ezlog.Err().L().M(errs.Errs()).Out()
 		}
+		// --offline with stale database
+		if len(global.DbRegistry.Stale) > 0 {
+			os.Exit(1)
+		}
 	},
 }
 
+// Record FROM image tag of each project in [dirs], their branches are added to the database on first use
+//   - Current directory if [dirs] is empty
+//   - Error is left to TypeDocker.New()
+func dbReference(dirs []string) {
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	for _, dir := range dirs {
+		docker := new(lib.TypeDocker).Scan(&dir)
+		if docker.Err == nil {
+			global.DbRegistry.Reference(docker.Distro, docker.Tag)
+		}
+	}
+}
+
+// Register package database constructor of each distro
+//   - Image names of `DistroImage` config are added to the registry defaults
+//   - Database is created, connected and updated(--updatedb) on first use
+func dbRegister() {
+	global.DbRegistry.New(global.Conf.DistroImage, global.Flag.UpdateDb)
+	for _, distro := range []string{"alpine", "wolfi"} {
+		global.DbRegistry.Register(distro, func() db.Idb {
+			property := db.TypeDbAlpineProperty{
+				DirCache:     &global.Conf.DirCache,
+				DirDbName:    &global.Conf.DirDB,
+				DistroName:   &distro,
+				AlpineBranch: &global.Conf.AlpineBranch,
+				AlpineArch:   &global.Conf.AlpineArch,
+				DirKeys:      &global.Conf.AlpineKeys,
+				VerifySign:   &global.Conf.AlpineVerify,
+				Concurrency:  &global.Conf.DbConcurrency,
+
+				AlpineMirrors: &global.Conf.AlpineMirrors,
+				AlpineRetry:   &global.Conf.AlpineRetry,
+				AlpineTimeout: &global.Conf.AlpineTimeout,
+
+				AlpineSecdbMirrors: &global.Conf.AlpineSecdbMirrors,
+				AlpineReleasesUrl:  &global.Conf.AlpineReleasesUrl,
+			}
+			if distro == "wolfi" {
+				property.AlpineBranch = nil
+				property.AlpineArch = &global.Conf.WolfiArch
+				property.VerifySign = &global.Conf.WolfiVerify
+				property.AlpineMirrors = &global.Conf.WolfiMirrors
+				property.AlpineSecdbMirrors = nil
+				property.AlpineReleasesUrl = nil
+			}
+			return new(db.TypeDbAlpine).
+				New(&property).
+				Connect()
+		})
+	}
+	global.DbRegistry.Register("archlinux", func() db.Idb {
+		property := db.TypeDbArchLinuxProperty{
+			DirCache:    &global.Conf.DirCache,
+			DirDbName:   &global.Conf.DirDB,
+			Concurrency: &global.Conf.DbConcurrency,
+
+			ArchLinuxMirrors: &global.Conf.ArchLinuxMirrors,
+		}
+		return new(db.TypeDbArchLinux).
+			New(&property).
+			Connect()
+	})
+	for _, distro := range []string{"debian", "ubuntu"} {
+		global.DbRegistry.Register(distro, func() db.Idb {
+			property := db.TypeDbDebianProperty{
+				DirCache:    &global.Conf.DirCache,
+				DirDbName:   &global.Conf.DirDB,
+				Concurrency: &global.Conf.DbConcurrency,
+				DistroName:  &distro,
+				DebianArch:  &global.Conf.DebianArch,
+			}
+			if distro == "debian" {
+				property.DebianBranch = &global.Conf.DebianBranch
+				property.DebianMirrors = &global.Conf.DebianMirrors
+				property.DebianSecurityMirrors = &global.Conf.DebianSecurityMirrors
+			} else {
+				property.DebianBranch = &global.Conf.UbuntuBranch
+				property.DebianMirrors = &global.Conf.UbuntuMirrors
+			}
+			return new(db.TypeDbDebian).
+				New(&property).
+				Connect()
+		})
+	}
+	for _, distro := range []string{"fedora", "ubi"} {
+		global.DbRegistry.Register(distro, func() db.Idb {
+			property := db.TypeDbRpmProperty{
+				DirCache:    &global.Conf.DirCache,
+				DirDbName:   &global.Conf.DirDB,
+				Concurrency: &global.Conf.DbConcurrency,
+				DistroName:  &distro,
+				RpmArch:     &global.Conf.RpmArch,
+			}
+			if distro == "fedora" {
+				property.RpmBranch = &global.Conf.FedoraBranch
+				property.RpmMirrors = &global.Conf.FedoraMirrors
+			} else {
+				property.RpmBranch = &global.Conf.UbiBranch
+				property.RpmMirrors = &global.Conf.UbiMirrors
+			}
+			return new(db.TypeDbRpm).
+				New(&property).
+				Connect()
+		})
+	}
+}
+
 func Execute() {
 	err := RootCmd.Execute()
 	if err != nil {
@@ -74,6 +185,7 @@ func Execute() {
 
 func init() {
 	RootCmd.PersistentFlags().BoolVarP(&global.Flag.Debug, "debug", "d", false, "enable debug")
+	RootCmd.PersistentFlags().StringVarP(&global.Flag.Distro, "distro", "", "alpine", "package database of db commands, distro or image name: alpine, debian, ubuntu, fedora, ubi, wolfi, archlinux")
 	RootCmd.PersistentFlags().BoolVarP(&global.Flag.UpdateDb, "updatedb", "u", false, "update DB")
 	RootCmd.PersistentFlags().BoolVarP(&global.Flag.Verbose, "verbose", "v", false, "enable verbose")
 	RootCmd.PersistentFlags().StringVarP(&global.Conf.FileConf, "config", "", lib.ConfDefault.FileConf, "config file")
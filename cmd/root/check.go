@@ -25,6 +25,9 @@ THE SOFTWARE.
 package root
 
 import (
+	"strings"
+	"time"
+
 	"github.com/J-Siu/go-auto-docker/global"
 	"github.com/J-Siu/go-auto-docker/lib"
 	"github.com/J-Siu/go-helper/v2/errs"
@@ -36,7 +39,7 @@ import (
 var checkCmd = &cobra.Command{
 	Use:     "check <docker path>",
 	Aliases: []string{"c"},
-	Short:   "Check Alpine package update",
+	Short:   "Check package update",
 	Run: func(cmd *cobra.Command, args []string) {
 		prefix := "Check"
 
@@ -58,18 +61,81 @@ var checkCmd = &cobra.Command{
 			// Dockerfile file
 			if err == nil {
 				docker.
-					New(&workPath, global.Db, global.Flag.Debug, global.Flag.Verbose)
+					New(&workPath, global.DbRegistry.Get, global.Conf.ProjectArchGet(workPath), global.Flag.Debug, global.Flag.Verbose)
 				err = docker.Err
 			}
 
 			if err == nil {
-				ezlog.Log().N(prefix).YesNo(docker.VerNew > docker.VerCurr).N(docker.Pkg).M(docker.VerCurr).M("->")
+				var (
+					updateAvailable = docker.UpdateAvailable()
+					verNewer        = docker.VerNewer(docker.VerNew, docker.VerCurr)
+					secfixes        []string
+					branchEol       = docker.BranchEol()
+					branchNewer     = docker.BranchNewer()
+				)
+				if global.FlagCheck.Security && verNewer {
+					secfixes = docker.Secfixes()
+				}
+				ezlog.Log().N(prefix).YesNo(updateAvailable).N(docker.Pkg).M(docker.VerCurr).M("->")
 				if docker.VerNew == "" {
 					ezlog.M("<package not found>")
 				} else {
 					ezlog.M(docker.VerNew)
 				}
 				ezlog.Out()
+				// Candidate version per target architecture
+				ezlog.Log().N(prefix).N(docker.Pkg).N("Arch")
+				for _, arch := range docker.Arch {
+					ver := docker.VerArch[arch]
+					if ver == "" {
+						ver = "<package not found>"
+					}
+					ezlog.M(arch + "=" + ver)
+				}
+				ezlog.Out()
+				if global.FlagCheck.Security && verNewer {
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Security")
+					if len(secfixes) > 0 {
+						ezlog.M(strings.Join(secfixes, " "))
+					} else {
+						ezlog.M("<no known fix>")
+					}
+					ezlog.Out()
+				}
+				// Branch end of life or superseded, see "db releases"
+				if branchEol != "" || branchNewer != "" {
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Branch").M(docker.Branch)
+					if branchEol != "" {
+						ezlog.M("end of life since " + branchEol)
+					}
+					if branchNewer != "" {
+						ezlog.M("superseded by " + branchNewer)
+					}
+					ezlog.Out()
+				}
+				if len(docker.HeldBack) > 0 && verNewer {
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Held back").M(docker.VerNew).M("not available on").M(strings.Join(docker.HeldBack, ",")).Out()
+				}
+				if len(docker.SubLag) > 0 && verNewer {
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Held back").M(docker.VerNew).M("not available for subpackage").M(strings.Join(docker.SubLag, ",")).Out()
+				}
+				if global.Flag.Verbose && docker.PkgInfo != nil {
+					info := docker.PkgInfo
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Repo").M(docker.Branch + "/" + docker.RepoNew).Out()
+					for sub := range docker.PkgSub {
+						ezlog.Log().N(prefix).N(docker.Pkg).N("Subpackage").M(sub).Out()
+					}
+					if docker.PkgDb != docker.Pkg {
+						ezlog.Log().N(prefix).N(docker.Pkg).N("Provider").M(docker.PkgDb).Out()
+					}
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Desc").M(info.Desc).Out()
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Url").M(info.Url).Out()
+					ezlog.Log().N(prefix).N(docker.Pkg).N("License").M(info.License).Out()
+					ezlog.Log().N(prefix).N(docker.Pkg).N("Origin").M(info.Origin).Out()
+					if info.BuildTime > 0 {
+						ezlog.Log().N(prefix).N(docker.Pkg).N("BuildTime").M(time.Unix(info.BuildTime, 0).UTC().Format(time.RFC3339)).Out()
+					}
+				}
 			}
 
 			errs.Queue("", err)
@@ -80,4 +146,6 @@ var checkCmd = &cobra.Command{
 func init() {
 	cmd := checkCmd
 	RootCmd.AddCommand(cmd)
+	cmd.Flags().BoolVarP(&global.FlagCheck.Security, "security", "s", false, "list security fixes(CVE) of available update, import with \"db secdb\"")
+	cmd.Flags().BoolVarP(&global.Flag.Offline, "offline", "", false, "do not download, warn and exit non-zero if database is older than DbMaxAge")
 }
@@ -25,6 +25,8 @@ THE SOFTWARE.
 package root
 
 import (
+	"strings"
+
 	"github.com/J-Siu/go-auto-docker/global"
 	"github.com/J-Siu/go-auto-docker/lib"
 	"github.com/J-Siu/go-helper/v2/errs"
@@ -36,7 +38,7 @@ import (
 var updateCmd = &cobra.Command{
 	Use:     "update <docker path>",
 	Aliases: []string{"u"},
-	Short:   "Update Alpine package version",
+	Short:   "Update package version",
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ezlog.Debug().N("FlagUpdate").Lm(&global.FlagUpdate).Out()
 	},
@@ -64,8 +66,10 @@ var updateCmd = &cobra.Command{
 			updateAvailable = false
 
 			if err == nil {
-				docker.New(&workPath, global.Db, global.Flag.Debug, global.Flag.Verbose)
-				updateAvailable = lib.VerNewer(docker.VerNew, docker.VerCurr)
+				docker.
+					New(&workPath, global.DbRegistry.Get, global.Conf.ProjectArchGet(workPath), global.Flag.Debug, global.Flag.Verbose).
+					BumpBranch(global.FlagUpdate.BumpBranch, global.Flag.Offline)
+				updateAvailable = docker.UpdateAvailable() || docker.Bumped()
 				ezlog.Debug().N(prefix).N("updateAvailable").M(updateAvailable).Out()
 				err = docker.Err
 			}
@@ -80,31 +84,54 @@ var updateCmd = &cobra.Command{
 			// Dockerfile file
 			if err == nil && updateAvailable {
 				docker.
-					New(&repo.DirCache, global.Db, global.Flag.Debug, global.Flag.Verbose).
+					New(&repo.DirCache, global.DbRegistry.Get, global.Conf.ProjectArchGet(workPath), global.Flag.Debug, global.Flag.Verbose).
+					BumpBranch(global.FlagUpdate.BumpBranch, global.Flag.Offline).
 					Update().
 					Dump(global.Flag.Debug).
 					BuildTest(global.FlagUpdate.BuildTest)
 				err = docker.Err
 			}
 
-			// CHANGELOG.md file
+			// CHANGELOG.md file, branch bump alone has no new version
 			if err == nil && docker.Updated() {
-				property := lib.TypeChangeLogProperty{
-					Dir:           &repo.DirCache,
-					FileChangeLog: &global.Conf.FileChangeLog,
-					Pkg:           &docker.Pkg,
-					VerCurr:       &docker.VerCurr,
-					VerNew:        &docker.VerNew,
+				verNewer := docker.VerNewer(docker.VerNew, docker.VerCurr)
+				var secfixes []string
+				if verNewer {
+					secfixes = docker.Secfixes()
+					property := lib.TypeChangeLogProperty{
+						Dir:           &repo.DirCache,
+						FileChangeLog: &global.Conf.FileChangeLog,
+						Pkg:           &docker.Pkg,
+						VerCurr:       &docker.VerCurr,
+						VerNew:        &docker.VerNew,
+						Secfixes:      &secfixes,
+						VerNewer:      docker.VerNewer,
+					}
+					changelog.
+						New(&property).
+						Update().
+						Dump(global.Flag.Debug)
+					err = changelog.Err
 				}
-				changelog.
-					New(&property).
-					Update().
-					Dump(global.Flag.Debug)
-				err = changelog.Err
 
 				// Repository commit and tag in cache(tmp)
 				if err == nil && global.FlagUpdate.Commit {
-					repo.Commit(docker.VerNew, global.FlagUpdate.Tag, true)
+					var (
+						body []string
+						msg  = docker.VerNew
+					)
+					if docker.Bumped() {
+						bump := "Bump base image to " + docker.Distro + ":" + docker.Tag
+						if verNewer {
+							body = append(body, bump)
+						} else {
+							msg = bump
+						}
+					}
+					if len(secfixes) > 0 {
+						body = append(body, "Fix "+strings.Join(secfixes, ", "))
+					}
+					repo.Commit(msg, strings.Join(body, "\n"), global.FlagUpdate.Tag && verNewer, true)
 					err = repo.Err
 				}
 
@@ -115,14 +142,22 @@ var updateCmd = &cobra.Command{
 			}
 
 			if err == nil {
+				verNewer := docker.VerNewer(docker.VerNew, docker.VerCurr)
 				ezlog.Log().N(prefix).N(str.YesNo(docker.Updated())).N(docker.Pkg).M(docker.VerCurr).M("->")
 				if docker.VerNew == "" {
 					ezlog.M("not found")
+				} else if len(docker.HeldBack) > 0 && verNewer {
+					ezlog.M(docker.VerNew).M("held back, not available on").M(strings.Join(docker.HeldBack, ","))
+				} else if len(docker.SubLag) > 0 && verNewer {
+					ezlog.M(docker.VerNew).M("held back, not available for subpackage").M(strings.Join(docker.SubLag, ","))
 				} else if docker.VerCurr == docker.VerNew {
 					ezlog.M("up to date")
 				} else {
 					ezlog.M(docker.VerNew)
 				}
+				if docker.Bumped() {
+					ezlog.M("branch").M(docker.BranchOld).M("->").M(docker.Branch)
+				}
 				ezlog.Out()
 			}
 
@@ -134,8 +169,10 @@ var updateCmd = &cobra.Command{
 func init() {
 	cmd := updateCmd
 	RootCmd.AddCommand(cmd)
+	cmd.Flags().BoolVarP(&global.FlagUpdate.BumpBranch, "bump-branch", "", false, "move FROM and /vX.Y/ repository URLs to newest stable branch, import with \"db releases\"")
 	cmd.Flags().BoolVarP(&global.FlagUpdate.Commit, "commit", "c", false, "apply git commit. Only work with -save")
 	cmd.Flags().BoolVarP(&global.FlagUpdate.BuildTest, "buildTest", "b", false, "so not perform docker build")
 	cmd.Flags().BoolVarP(&global.FlagUpdate.Save, "save", "s", false, "write back to project folder (cancel on error)")
 	cmd.Flags().BoolVarP(&global.FlagUpdate.Tag, "tag", "t", false, "apply git tag. (only work with --commit)")
+	cmd.Flags().BoolVarP(&global.Flag.Offline, "offline", "", false, "do not download, warn and exit non-zero if database is older than DbMaxAge")
 }
@@ -0,0 +1,137 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"archive/tar"
+	"bufio"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Parse one pacman sync database "desc" file from [r]
+//
+//   - Each field is "%NAME%" line followed by value lines, ended by empty line
+//   - `Origin` is %BASE%(pkgbase), same as `Pkg` if absent
+//   - %CONFLICTS% is stored as conflict in `Depends`
+func alpmDescParse(r io.Reader, branch, repo, arch string) (record *TypeDbAlpineRecord, err error) {
+	var (
+		field   string
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	record = &TypeDbAlpineRecord{
+		Branch: branch,
+		Repo:   repo,
+		Arch:   arch,
+	}
+	for scanner.Scan() {
+		v := strings.TrimSpace(scanner.Text())
+		if v == "" {
+			field = ""
+			continue
+		}
+		if strings.HasPrefix(v, "%") && strings.HasSuffix(v, "%") {
+			field = v
+			continue
+		}
+		switch field {
+		case "%NAME%":
+			record.Pkg = v
+		case "%BASE%":
+			record.Origin = v
+		case "%VERSION%":
+			record.Ver = v
+		case "%DESC%":
+			record.Desc = v
+		case "%CSIZE%":
+			record.Size, _ = strconv.ParseInt(v, 10, 64)
+		case "%ISIZE%":
+			record.InstalledSize, _ = strconv.ParseInt(v, 10, 64)
+		case "%SHA256SUM%":
+			record.Checksum = v
+		case "%URL%":
+			record.Url = v
+		case "%LICENSE%":
+			if record.License != "" {
+				v = record.License + " AND " + v
+			}
+			record.License = v
+		case "%ARCH%":
+			record.PkgArch = v
+		case "%BUILDDATE%":
+			record.BuildTime, _ = strconv.ParseInt(v, 10, 64)
+		case "%PACKAGER%":
+			record.Maintainer = v
+		case "%DEPENDS%":
+			record.Depends = append(record.Depends, TypeDbAlpineDepend{apkDepSplit(v)})
+		case "%CONFLICTS%":
+			dep := apkDepSplit(v)
+			dep.Conflict = true
+			record.Depends = append(record.Depends, TypeDbAlpineDepend{dep})
+		case "%PROVIDES%":
+			record.Provides = append(record.Provides, TypeDbAlpineProvide{apkDepSplit(v)})
+		}
+	}
+	if record.Origin == "" {
+		record.Origin = record.Pkg
+	}
+	err = scanner.Err()
+	return record, err
+}
+
+// Read pacman sync database(<repo>.db) from [r] without extracting to disk
+//   - Compression is detected from content
+//   - Only "<pkg>-<ver>/desc" entries are read
+func alpmDbRead(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var (
+		header *tar.Header
+		rc     io.ReadCloser
+		record *TypeDbAlpineRecord
+	)
+	rc, err = decompressReader(r)
+	if err == nil {
+		defer rc.Close()
+		tr := tar.NewReader(rc)
+		for err == nil {
+			header, err = tr.Next()
+			if err != nil {
+				break
+			}
+			if path.Base(header.Name) == "desc" {
+				record, err = alpmDescParse(tr, branch, repo, arch)
+				if err == nil && record.Pkg != "" {
+					rows = append(rows, *record)
+				}
+			}
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	return rows, err
+}
@@ -0,0 +1,219 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	apkIndexDescription = "DESCRIPTION"
+	apkIndexSignPrefix  = ".SIGN."
+)
+
+// Content of an APKINDEX.tar.gz
+type TypeApkIndexTgz struct {
+	Description string               `json:"Description,omitempty"` // DESCRIPTION
+	SignName    string               `json:"SignName,omitempty"`    // .SIGN.RSA.<key name>
+	Sign        []byte               `json:"-"`                     // signature
+	Rows        []TypeDbAlpineRecord `json:"-"`                     // APKINDEX
+}
+
+// APKINDEX dependency style entry, used by `D:`, `p:` and `i:`
+//
+//   - "so:libc.musl-x86_64.so.1" -> Name
+//   - "busybox>=1.36" -> Name, Op, Ver
+//   - "!foo" -> Name, Conflict
+type TypeDbAlpineDep struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	RecordID uint   `json:"-" gorm:"index"`
+	Name     string `json:"Name" gorm:"index"`
+	Op       string `json:"Op,omitempty"`
+	Ver      string `json:"Ver,omitempty"`
+	Conflict bool   `json:"Conflict,omitempty"`
+}
+
+// `D:` runtime dependencies
+type TypeDbAlpineDepend struct{ TypeDbAlpineDep }
+
+// `p:` provides
+type TypeDbAlpineProvide struct{ TypeDbAlpineDep }
+
+// `i:` install_if
+type TypeDbAlpineInstallIf struct{ TypeDbAlpineDep }
+
+// Return dependency string in APKINDEX format
+func (t *TypeDbAlpineDep) String() (s string) {
+	if t.Conflict {
+		s = "!"
+	}
+	return s + t.Name + t.Op + t.Ver
+}
+
+// Split APKINDEX dependency string into [TypeDbAlpineDep]
+func apkDepSplit(s string) (dep TypeDbAlpineDep) {
+	if strings.HasPrefix(s, "!") {
+		dep.Conflict = true
+		s = s[1:]
+	}
+	i := strings.IndexAny(s, "<>=~")
+	if i < 0 {
+		dep.Name = s
+		return dep
+	}
+	dep.Name = s[:i]
+	j := i
+	for j < len(s) && strings.ContainsRune("<>=~", rune(s[j])) {
+		j++
+	}
+	dep.Op = s[i:j]
+	dep.Ver = s[j:]
+	return dep
+}
+
+// Parse APKINDEX content from [r]
+//
+//   - Records are separated by empty line
+//   - Each line is in "<field>:<value>" format
+//   - Unknown fields are ignored
+func apkIndexParse(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var (
+		record  *TypeDbAlpineRecord
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	flush := func() {
+		if record != nil && record.Pkg != "" {
+			rows = append(rows, *record)
+		}
+		record = nil
+	}
+	for scanner.Scan() {
+		l := scanner.Text()
+		if len(l) < 2 || l[1] != ':' {
+			if len(l) == 0 {
+				flush()
+			}
+			continue
+		}
+		if record == nil {
+			record = &TypeDbAlpineRecord{
+				Branch: branch,
+				Repo:   repo,
+				Arch:   arch,
+			}
+		}
+		v := l[2:]
+		switch l[0] {
+		case 'C':
+			record.Checksum = v
+		case 'P':
+			record.Pkg = v
+		case 'V':
+			record.Ver = v
+		case 'A':
+			record.PkgArch = v
+		case 'S':
+			record.Size, _ = strconv.ParseInt(v, 10, 64)
+		case 'I':
+			record.InstalledSize, _ = strconv.ParseInt(v, 10, 64)
+		case 'T':
+			record.Desc = v
+		case 'U':
+			record.Url = v
+		case 'L':
+			record.License = v
+		case 'o':
+			record.Origin = v
+		case 'm':
+			record.Maintainer = v
+		case 't':
+			record.BuildTime, _ = strconv.ParseInt(v, 10, 64)
+		case 'c':
+			record.Commit = v
+		case 'k':
+			record.ProviderPriority, _ = strconv.Atoi(v)
+		case 'D':
+			for _, s := range strings.Fields(v) {
+				record.Depends = append(record.Depends, TypeDbAlpineDepend{apkDepSplit(s)})
+			}
+		case 'p':
+			for _, s := range strings.Fields(v) {
+				record.Provides = append(record.Provides, TypeDbAlpineProvide{apkDepSplit(s)})
+			}
+		case 'i':
+			for _, s := range strings.Fields(v) {
+				record.InstallIf = append(record.InstallIf, TypeDbAlpineInstallIf{apkDepSplit(s)})
+			}
+		}
+	}
+	flush()
+	err = scanner.Err()
+	return rows, err
+}
+
+// Read APKINDEX.tar.gz from [r] without extracting to disk
+//
+//   - APKINDEX is parsed into `Rows`
+//   - DESCRIPTION and .SIGN.* are kept
+//   - APKINDEX.tar.gz is 2 concatenated gzip streams(signature + index), read as one
+func apkIndexTgzRead(r io.Reader, fileIndex, branch, repo, arch string) (idx *TypeApkIndexTgz, err error) {
+	var (
+		gz     *gzip.Reader
+		header *tar.Header
+		b      []byte
+	)
+	idx = new(TypeApkIndexTgz)
+	gz, err = gzip.NewReader(r)
+	if err == nil {
+		defer gz.Close()
+		tr := tar.NewReader(gz)
+		for err == nil {
+			header, err = tr.Next()
+			if err != nil {
+				break
+			}
+			switch {
+			case header.Name == fileIndex:
+				idx.Rows, err = apkIndexParse(tr, branch, repo, arch)
+			case header.Name == apkIndexDescription:
+				b, err = io.ReadAll(tr)
+				idx.Description = strings.TrimSpace(string(b))
+			case strings.HasPrefix(header.Name, apkIndexSignPrefix):
+				idx.SignName = header.Name
+				idx.Sign, err = io.ReadAll(tr)
+			}
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	return idx, err
+}
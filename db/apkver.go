@@ -0,0 +1,134 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Alpine package version, eg. 1.2.3a_rc1_p2-r4
+var apkVerRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)*)([a-z]?)((?:_[a-z]+[0-9]*)*)(?:-r([0-9]+))?$`)
+
+// Suffix order of apk, pre-release is negative, post-release is positive, no suffix is 0
+var apkVerSuffix = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"cvs":   1,
+	"svn":   2,
+	"git":   3,
+	"hg":    4,
+	"p":     5,
+}
+
+// Parsed Alpine package version
+type apkVer struct {
+	nums     []string // digits of each "." component
+	letter   string   // single letter after digits, eg. "a" in 1.2a
+	suffix   []int    // rank in `apkVerSuffix` of each suffix
+	suffixNo []string // digits after each suffix, eg. "1" in _rc1
+	revision int      // -rN, -1 if none
+}
+
+// Return true if Alpine(apk) version [v1] > [v2]
+func ApkVerNewer(v1, v2 string) bool { return ApkVerCmp(v1, v2) > 0 }
+
+// Compare Alpine(apk) version [v1] and [v2]
+//   - Format: digits{.digits}[letter]{_suffix[digits]}[-rN]
+//   - Suffix order: _alpha < _beta < _pre < _rc < no suffix < _cvs < _svn < _git < _hg < _p
+//   - Version not in apk format is compared like rpm, segment by segment
+//   - Return -1, 0, 1 if [v1] is older, same, newer than [v2]
+func ApkVerCmp(v1, v2 string) int {
+	a, okA := apkVerParse(v1)
+	b, okB := apkVerParse(v2)
+	if !okA || !okB {
+		return rpmVerCmp(v1, v2)
+	}
+	for i := range min(len(a.nums), len(b.nums)) {
+		if c := apkVerNumCmp(a.nums[i], b.nums[i]); c != 0 {
+			return c
+		}
+	}
+	if len(a.nums) != len(b.nums) {
+		return cmp.Compare(len(a.nums), len(b.nums))
+	}
+	if c := strings.Compare(a.letter, b.letter); c != 0 {
+		return c
+	}
+	for i := range min(len(a.suffix), len(b.suffix)) {
+		if c := cmp.Compare(a.suffix[i], b.suffix[i]); c != 0 {
+			return c
+		}
+		if c := apkVerNumCmp(a.suffixNo[i], b.suffixNo[i]); c != 0 {
+			return c
+		}
+	}
+	// extra suffix is older if pre-release, newer if post-release
+	if len(a.suffix) > len(b.suffix) {
+		return cmp.Compare(a.suffix[len(b.suffix)], 0)
+	}
+	if len(b.suffix) > len(a.suffix) {
+		return cmp.Compare(0, b.suffix[len(a.suffix)])
+	}
+	return cmp.Compare(a.revision, b.revision)
+}
+
+// Parse Alpine version [v], false if not in apk format
+func apkVerParse(v string) (ver apkVer, ok bool) {
+	match := apkVerRegexp.FindStringSubmatch(strings.TrimSpace(v))
+	if match == nil {
+		return ver, false
+	}
+	ver.nums = strings.Split(match[1], ".")
+	ver.letter = match[2]
+	for _, suffix := range strings.Split(match[3], "_")[1:] {
+		name := strings.TrimRight(suffix, "0123456789")
+		rank, found := apkVerSuffix[name]
+		if !found {
+			return ver, false
+		}
+		ver.suffix = append(ver.suffix, rank)
+		ver.suffixNo = append(ver.suffixNo, suffix[len(name):])
+	}
+	ver.revision = -1
+	if match[4] != "" {
+		ver.revision, _ = strconv.Atoi(match[4])
+	}
+	return ver, true
+}
+
+// Compare digits [a] and [b] as numbers of any length, empty is 0
+func apkVerNumCmp(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,59 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import "testing"
+
+func TestApkVerNewer(t *testing.T) {
+	for _, c := range []struct {
+		v1, v2 string
+		newer  bool
+	}{
+		{"1.37.0-r18", "1.36.1-r14", true},
+		{"1.10-r0", "1.9-r0", true},
+		{"1.0-r10", "1.0-r9", true},
+		{"1.0-r0", "1.0-r0", false},
+		{"1.0.1-r0", "1.0-r0", true},
+		{"1.0a-r0", "1.0-r0", true},
+		{"1.0b-r0", "1.0a-r0", true},
+		{"1.0_rc1-r0", "1.0-r0", false},
+		{"1.0-r0", "1.0_rc1-r0", true},
+		{"1.0_beta-r0", "1.0_alpha-r0", true},
+		{"1.0_pre1-r0", "1.0_beta2-r0", true},
+		{"1.0_rc2-r0", "1.0_rc1-r0", true},
+		{"1.0_rc10-r0", "1.0_rc9-r0", true},
+		{"1.0_p1-r0", "1.0-r0", true},
+		{"1.0_p1-r0", "1.0_git20240101-r0", true},
+		{"1.0_git20240101-r0", "1.0-r5", true},
+		{"1.0_rc1_p1-r0", "1.0_rc1-r0", true},
+		{"1.0_rc1_alpha-r0", "1.0_rc1-r0", false},
+		{"1.0-r1", "1.0", true},
+		{"2.0", "10.0", false},
+	} {
+		if newer := ApkVerNewer(c.v1, c.v2); newer != c.newer {
+			t.Errorf("ApkVerNewer(%q, %q) = %v, want %v", c.v1, c.v2, newer, c.newer)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"cmp"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Branch directory in mirror listing, eg. <a href="v3.22/">
+var branchListing = regexp.MustCompile(`href="(edge|latest-stable|v[0-9]+\.[0-9]+)/"`)
+
+// Mirror listing cached in database is downloaded again after this
+const branchListingMaxAge = 24 * time.Hour
+
+// Branch in mirror directory listing, cached for Branches()
+type TypeDbAlpineBranch struct {
+	Branch    string    `json:"Branch" gorm:"primaryKey"`
+	FetchedAt time.Time `json:"FetchedAt"`
+}
+
+// Branches return available branches, edge and latest-stable first, then stable branches newest first
+//   - From release metadata if imported(see ReleaseUpdate), else directory listing of the first working mirror
+//   - Directory listing is cached in database for `branchListingMaxAge`
+//   - Return `Branch` if discovery is not supported or failed, eg. Wolfi
+func (t *TypeDbAlpine) Branches() (branches []string) {
+	prefix := t.MyType + ".Branches"
+	if t.CheckErrInit(prefix) {
+		if t.Db == nil && t.BranchDiscover {
+			t.Connect()
+		}
+		if t.branches == nil && t.BranchDiscover && t.Base.Err == nil {
+			t.branches = t.branchRelease()
+			if len(t.branches) == 0 {
+				t.branches = t.branchListing()
+			}
+			branchSort(t.branches)
+			ezlog.Debug().N(prefix).M(t.branches).Out()
+		}
+		branches = t.branches
+		if len(branches) == 0 {
+			branches = t.Branch
+		}
+	}
+	return branches
+}
+
+// BranchAdd add [branches] to `Branch`, eg. branches referenced by Dockerfile FROM
+//   - Branch not in Branches() is skipped with error queued
+//   - Indexes of added branches not in database are downloaded, failure is queued, not set
+//   - Do nothing if discovery is not supported
+func (t *TypeDbAlpine) BranchAdd(branches []string) Idb {
+	prefix := t.MyType + ".BranchAdd"
+	if t.CheckErrInit(prefix) && t.BranchDiscover {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		var (
+			added     []string
+			available = t.Branches()
+			missing   []string
+		)
+		for _, branch := range branches {
+			if branch == "" || slices.Contains(t.Branch, branch) || slices.Contains(added, branch) {
+				continue
+			}
+			if !slices.Contains(available, branch) {
+				errs.Queue(prefix, errors.New(t.Distro+" branch "+branch+" not available"))
+				continue
+			}
+			added = append(added, branch)
+		}
+		t.Branch = append(slices.Clone(t.Branch), added...)
+		if t.Db == nil {
+			t.Connect()
+		}
+		for _, branch := range added {
+			var count int64
+			if t.Base.Err == nil {
+				t.Base.Err = t.Db.Model(&TypeDbAlpineIndex{}).Where("branch = ? AND hash != ''", branch).Count(&count).Error
+			}
+			if count == 0 {
+				missing = append(missing, branch)
+			}
+		}
+		ezlog.Debug().N(prefix).N("added").M(added).N("missing").M(missing).Out()
+		if t.Base.Err == nil && len(missing) > 0 {
+			ezlog.Log().N(t.Distro).M("db update").M(strings.Join(missing, ",")).Out()
+			t.stageUpdate(func() []string {
+				return t.idxUpdate(func(f func(branch, repo, arch string)) { t.idxEachBranch(missing, f) }, t.idxFetch)
+			})
+			// branch failed to fetch is reported, other branches and database are still usable
+			errs.Queue(prefix, t.ErrClear())
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Return [branch] with "latest-stable" mapped to the actual stable branch, eg. "v3.22"
+//   - From release metadata if imported, else newest stable branch of [candidates], eg. Branches()
+//   - Return empty string if "latest-stable" cannot be mapped, other branches as is
+func (t *TypeDbAlpine) branchStable(branch string, candidates func() []string) string {
+	if branch != "latest-stable" {
+		return branch
+	}
+	latest := t.BranchLatest()
+	if latest == "" {
+		branches := slices.DeleteFunc(slices.Clone(candidates()), func(b string) bool { return !BranchIsStable(b) })
+		branchSort(branches)
+		if len(branches) > 0 {
+			latest = branches[0]
+		}
+	}
+	return latest
+}
+
+// Return branches in release metadata, with latest-stable if set
+func (t *TypeDbAlpine) branchRelease() (branches []string) {
+	if t.Db != nil {
+		var rows []TypeDbAlpineRelease
+		t.Db.Where("repo = ?", "").Find(&rows)
+		for _, row := range rows {
+			branches = append(branches, row.Branch)
+			if row.Latest {
+				branches = append(branches, "latest-stable")
+			}
+		}
+	}
+	return branches
+}
+
+// Return branches in directory listing of the first working mirror
+//   - Listing cached in database is used if fetched within `branchListingMaxAge`, or download failed
+//   - Error is queued, not set, branches are still usable without discovery
+func (t *TypeDbAlpine) branchListing() (branches []string) {
+	prefix := t.MyType + ".branchListing"
+	var cached []TypeDbAlpineBranch
+	t.Db.Order("branch").Find(&cached)
+	if len(cached) > 0 && time.Since(cached[0].FetchedAt) < branchListingMaxAge {
+		for _, row := range cached {
+			branches = append(branches, row.Branch)
+		}
+		return branches
+	}
+	var (
+		client  = downloadClient(t.Timeout)
+		err     = os.MkdirAll(t.DirDb, os.ModePerm)
+		errMsgs []string
+		file    = path.Join(t.DirDb, "branches.html")
+	)
+	for _, mirror := range t.Mirrors {
+		if err != nil {
+			break
+		}
+		var (
+			data       []byte
+			urlListing string
+		)
+		urlListing, err = url.JoinPath(mirror, "/")
+		if err == nil {
+			_, err = downloadRetry(client, urlListing, file, "", "", t.Retry)
+		}
+		if err == nil {
+			data, err = os.ReadFile(file)
+		}
+		if err == nil {
+			for _, match := range branchListing.FindAllStringSubmatch(string(data), -1) {
+				if !slices.Contains(branches, match[1]) {
+					branches = append(branches, match[1])
+				}
+			}
+			if len(branches) > 0 {
+				break
+			}
+			err = errors.New(urlListing + " no branch found")
+		}
+		errMsgs = append(errMsgs, err.Error())
+		err = nil
+	}
+	if err == nil && len(branches) == 0 {
+		err = errors.New("all mirrors failed: " + strings.Join(errMsgs, "; "))
+	}
+	if len(branches) > 0 {
+		now := time.Now()
+		rows := make([]TypeDbAlpineBranch, 0, len(branches))
+		for _, branch := range branches {
+			rows = append(rows, TypeDbAlpineBranch{Branch: branch, FetchedAt: now})
+		}
+		err = t.Db.Transaction(func(tx *gorm.DB) (err error) {
+			err = tx.Where("1 = 1").Delete(&TypeDbAlpineBranch{}).Error
+			if err == nil {
+				err = tx.Create(&rows).Error
+			}
+			return err
+		})
+	} else {
+		for _, row := range cached {
+			branches = append(branches, row.Branch)
+		}
+	}
+	errs.Queue(prefix, err)
+	return branches
+}
+
+// Sort [branches], edge and latest-stable first, then stable branches newest first
+func branchSort(branches []string) {
+	slices.SortFunc(branches, func(a, b string) int {
+		switch {
+		case BranchIsStable(a) && BranchIsStable(b):
+			if VerNewer(a[1:], b[1:]) {
+				return -1
+			}
+			if VerNewer(b[1:], a[1:]) {
+				return 1
+			}
+			return 0
+		case BranchIsStable(a):
+			return 1
+		case BranchIsStable(b):
+			return -1
+		}
+		return cmp.Compare(a, b)
+	})
+}
@@ -24,11 +24,44 @@ THE SOFTWARE.
 
 package db
 
+import (
+	"io"
+	"time"
+)
+
 type Idb interface {
+	ArchFromPlatform(platform string) string
+	ArchGet() []string
+	BranchAdd(branches []string) Idb
+	BranchEol(branch, repo string) (eol string)
+	BranchFromImage(image, tag string) string
+	BranchLatest() (branch string)
+	Branches() (branches []string)
 	Connect() Idb
+	Deps(pkg, branch, repo, arch string, recursive bool) *[]*[]string
 	Dump(bool) Idb
 	Update() Idb
 	Err() error
-	Search(pkg string, exact bool) *[]*[]string
-	VerGet(pkg string, branch, repo string) (ver *string)
+	ErrClear() error
+	Export(w io.Writer, format, branch, repo, arch string) (count int)
+	History(pkg string) *[]*[]string
+	Import(src string) Idb
+	Info(pkg string, branch, repo, arch, ver string) *TypeDbAlpineRecord
+	OriginPkgs(pkg, branch string) (pkgs []string)
+	PkgResolve(name, branch string) (pkg string)
+	PkgVerSep() string
+	Rdeps(pkg, branch, repo, arch string) *[]*[]string
+	ReleaseUpdate(file string) Idb
+	RepoGet() []string
+	SecdbUpdate(files []string) Idb
+	Stale(branches []string, maxAge time.Duration) (names []string)
+	Status(maxAge time.Duration) *[]*[]string
+	Secfixes(pkg, branch, verFrom, verTo string) (ids []string)
+	Search(pkg string, option *TypeDbSearchOption) *[]*[]string
+	SearchProvides(name string, option *TypeDbSearchOption) *[]*[]string
+	SearchText(text string, option *TypeDbSearchOption) *[]*[]string
+	Suggest(name string, option *TypeDbSearchOption) []string
+	VerGet(pkg string, branch, repo, arch string) (ver *string)
+	VerNewer(v1, v2 string) bool
+	Verify() *[]*[]string
 }
@@ -25,19 +25,26 @@ THE SOFTWARE.
 package db
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/J-Siu/go-helper/v2/array"
 	"github.com/J-Siu/go-helper/v2/basestruct"
-	"github.com/J-Siu/go-helper/v2/cmd"
 	"github.com/J-Siu/go-helper/v2/errs"
 	"github.com/J-Siu/go-helper/v2/ezlog"
+	"github.com/J-Siu/go-helper/v2/file"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -47,54 +54,174 @@ const extTgz = ".tar.gz"
 
 var DbAlpineDefault = TypeDbAlpine{
 	FileIndex: "APKINDEX",
-	UrlBase:   "http://dl-cdn.alpinelinux.org/alpine",
+	IndexPath: "{branch}/{repo}/{arch}",
+	Mirrors:   []string{"http://dl-cdn.alpinelinux.org/alpine"},
+	Retry:     2,
+	Timeout:   30 * time.Second,
+
+	SecdbMirrors: []string{"https://secdb.alpinelinux.org"},
+	SecdbPath:    "{branch}/{repo}.json",
+
+	ReleasesUrl: "https://alpinelinux.org/releases.json",
+
+	Distro:         "alpine",
+	Branch:         []string{"latest-stable", "edge"},
+	BranchDiscover: true,
+	Repository:     []string{"community", "main", "testing"},
+	RepoDefault:    []string{"main", "community"},
+	Arch:           []string{"aarch64", "armhf", "armv7", "x86", "x86_64"},
+}
+
+// Wolfi use APKINDEX, but is rolling with a single "os" repository
+var DbWolfiDefault = TypeDbAlpine{
+	FileIndex: "APKINDEX",
+	IndexPath: "{repo}/{arch}",
+	Mirrors:   []string{"https://packages.wolfi.dev"},
+	Retry:     2,
+	Timeout:   30 * time.Second,
+
+	SecdbMirrors: []string{"https://packages.wolfi.dev"},
+	SecdbPath:    "{repo}/security.json",
+
+	Distro:        "wolfi",
+	Branch:        []string{"rolling"},
+	BranchRolling: "rolling",
+	Repository:    []string{"os"},
+	RepoDefault:   []string{"os"},
+	Arch:          []string{"aarch64", "x86_64"},
+}
+
+// Docker platform to Alpine architecture
+var AlpinePlatformArch = map[string]string{
+	"linux/386":      "x86",
+	"linux/amd64":    "x86_64",
+	"linux/arm/v6":   "armhf",
+	"linux/arm/v7":   "armv7",
+	"linux/arm64":    "aarch64",
+	"linux/arm64/v8": "aarch64",
+	"linux/ppc64le":  "ppc64le",
+	"linux/riscv64":  "riscv64",
+	"linux/s390x":    "s390x",
+}
+
+// Return Alpine architecture of [platform], eg. "linux/amd64" -> "x86_64"
+//   - [platform] is returned as is if it is not a known Docker platform
+func AlpineArchFromPlatform(platform string) string {
+	if arch, ok := AlpinePlatformArch[strings.ToLower(platform)]; ok {
+		return arch
+	}
+	return platform
+}
 
-	Distro:     "alpine",
-	Branch:     []string{"latest-stable", "edge"},
-	Repository: []string{"community", "main", "testing"},
-	Arch:       []string{"aarch64", "armhf", "armv7", "x86", "x86_64"},
+type TypeDbAlpineProperty struct {
+	DirCache     *string   `json:"DirCache"`     // Full path of cache directory
+	DirDbName    *string   `json:"DirDbName"`    // Directory name, not full path, of database
+	DistroName   *string   `json:"DistroName"`   // "alpine" or "wolfi", use alpine if empty
+	AlpineBranch *[]string `json:"AlpineBranch"` // Branch list, use default if empty
+	AlpineArch   *[]string `json:"AlpineArch"`   // Architecture list, use default if empty
+	DirKeys      *string   `json:"DirKeys"`      // Directory of trusted Alpine public keys
+	VerifySign   *bool     `json:"VerifySign"`   // Verify APKINDEX signature
+	Concurrency  *int      `json:"Concurrency"`  // Number of parallel index download
+
+	AlpineMirrors *[]string `json:"AlpineMirrors"` // Base URL of mirrors, in order of preference, use default if empty
+	AlpineRetry   *int      `json:"AlpineRetry"`   // Retry per mirror
+	AlpineTimeout *int      `json:"AlpineTimeout"` // Timeout per request in second
+
+	AlpineSecdbMirrors *[]string `json:"AlpineSecdbMirrors"` // Base URL of security database mirrors, use default if empty
+	AlpineReleasesUrl  *string   `json:"AlpineReleasesUrl"`  // URL of release metadata(releases.json), use default if empty
 }
 
 // Alpine package database struct base on repo, branch and arch
 type TypeDbAlpine struct {
 	*basestruct.Base
+	*TypeDbAlpineProperty
 
 	Db        *gorm.DB
 	DirDb     string // full path of base database (db file + APKINDEX) directory
 	FileDb    string // full path of the database file
 	FileIndex string
-	UrlBase   string
-
-	Distro     string
-	Branch     []string
-	Repository []string
-	Arch       []string
+	IndexPath string        // Path of index directory under mirror, "{branch}", "{repo}" and "{arch}" are replaced
+	Mirrors   []string      // Base URL of mirrors, in order of preference
+	Retry     int           // Retry per mirror
+	Timeout   time.Duration // Timeout per request
+	client    *http.Client
+	verNewer  func(v1, v2 string) bool // version comparison of distro, for methods of embedding backends, eg. ApkVerNewer
+	verify    bool                     // Verify APKINDEX signature in current update, see stageUpdate()
+
+	SecdbMirrors []string // Base URL of security database(secdb) mirrors, in order of preference
+	SecdbPath    string   // Path of secdb file under mirror, "{branch}" and "{repo}" are replaced
+	ReleasesUrl  string   // URL of release metadata(releases.json), empty if not supported
+
+	Distro         string
+	Branch         []string
+	BranchRolling  string // Branch of all image tags if distro is rolling, eg. Wolfi
+	BranchDiscover bool   // Branches are discovered and added on reference, see BranchAdd()
+	branches       []string
+	Repository     []string
+	RepoDefault    []string // Repositories checked for a Dockerfile
+	Arch           []string
 }
 
+// One APKINDEX record
+//
+//   - `Arch` is the repository architecture, `PkgArch`(A:) can be "noarch"
+//   - `Depends`(D:), `Provides`(p:) and `InstallIf`(i:) are stored in child tables
 type TypeDbAlpineRecord struct {
 	// gorm.Model
+	ID     uint   `json:"-" gorm:"primaryKey"`
 	Pkg    string `json:"Pkg"`
 	Branch string `json:"Branch"`
 	Repo   string `json:"Repo"`
 	Arch   string `json:"Arch"`
 	Ver    string `json:"Ver"`
+
+	Epoch       string `json:"Epoch,omitempty"`       // rpm only
+	VerUpstream string `json:"VerUpstream,omitempty"` // rpm only, version without epoch and release
+	Release     string `json:"Release,omitempty"`     // rpm only
+
+	Checksum         string `json:"Checksum,omitempty"`         // C:
+	PkgArch          string `json:"PkgArch,omitempty"`          // A:
+	Size             int64  `json:"Size,omitempty"`             // S:
+	InstalledSize    int64  `json:"InstalledSize,omitempty"`    // I:
+	Desc             string `json:"Desc,omitempty"`             // T:
+	Url              string `json:"Url,omitempty"`              // U:
+	License          string `json:"License,omitempty"`          // L:
+	Origin           string `json:"Origin,omitempty"`           // o:
+	Maintainer       string `json:"Maintainer,omitempty"`       // m:
+	BuildTime        int64  `json:"BuildTime,omitempty"`        // t: unix timestamp
+	Commit           string `json:"Commit,omitempty"`           // c: aports commit
+	ProviderPriority int    `json:"ProviderPriority,omitempty"` // k:
+
+	Depends   []TypeDbAlpineDepend    `json:"Depends,omitempty" gorm:"foreignKey:RecordID;constraint:OnDelete:CASCADE"`
+	Provides  []TypeDbAlpineProvide   `json:"Provides,omitempty" gorm:"foreignKey:RecordID;constraint:OnDelete:CASCADE"`
+	InstallIf []TypeDbAlpineInstallIf `json:"InstallIf,omitempty" gorm:"foreignKey:RecordID;constraint:OnDelete:CASCADE"`
 }
 
 func (t *TypeDbAlpine) Err() error {
 	return t.Base.Err
 }
 
-func (t *TypeDbAlpine) New(dirCache, dirDb *string, alpineBranch *[]string) *TypeDbAlpine {
+// ErrClear return and clear error, eg. database is still usable after a failed update
+func (t *TypeDbAlpine) ErrClear() (err error) {
+	err = t.Base.Err
+	t.Base.Err = nil
+	return err
+}
+
+func (t *TypeDbAlpine) New(property *TypeDbAlpineProperty) *TypeDbAlpine {
 	t.Base = new(basestruct.Base)
+	t.TypeDbAlpineProperty = property
 	t.Initialized = true
 	t.MyType = "TypeDbAlpine"
 	prefix := t.MyType + ".init"
 	ezlog.Debug().N(prefix).TxtStart().Out()
 
-	t.DirDb = path.Join(*dirCache, *dirDb, t.Distro)
-	t.FileDb = path.Join(*dirCache, *dirDb, t.Distro, t.Distro+".db")
+	t.setDefault(t.AlpineBranch)
+	t.verNewer = ApkVerNewer
 
-	t.setDefault(alpineBranch)
+	// after setDefault(), `Distro` is needed
+	t.DirDb = path.Join(*t.DirCache, *t.DirDbName, t.Distro)
+	t.FileDb = path.Join(t.DirDb, t.Distro+".db")
 
 	ezlog.Debug().N(prefix).Lm(t).Out()
 
@@ -103,16 +230,49 @@ func (t *TypeDbAlpine) New(dirCache, dirDb *string, alpineBranch *[]string) *Typ
 }
 
 func (t *TypeDbAlpine) setDefault(alpineBranch *[]string) *TypeDbAlpine {
-	t.Arch = DbAlpineDefault.Arch
-	t.Repository = DbAlpineDefault.Repository
-	t.Distro = DbAlpineDefault.Distro
-	t.Branch = DbAlpineDefault.Branch
-	t.FileIndex = DbAlpineDefault.FileIndex
-	t.UrlBase = DbAlpineDefault.UrlBase
-
-	if len(*alpineBranch) == 0 {
-		t.Branch = DbAlpineDefault.Branch
-	} else {
+	def := &DbAlpineDefault
+	if t.DistroName != nil && strings.ToLower(*t.DistroName) == DbWolfiDefault.Distro {
+		def = &DbWolfiDefault
+	}
+	t.Arch = def.Arch
+	t.Repository = def.Repository
+	t.RepoDefault = def.RepoDefault
+	t.Distro = def.Distro
+	t.Branch = def.Branch
+	t.BranchRolling = def.BranchRolling
+	t.BranchDiscover = def.BranchDiscover
+	t.FileIndex = def.FileIndex
+	t.IndexPath = def.IndexPath
+	t.Mirrors = def.Mirrors
+	t.Retry = def.Retry
+	t.Timeout = def.Timeout
+	t.SecdbMirrors = def.SecdbMirrors
+	t.SecdbPath = def.SecdbPath
+	t.ReleasesUrl = def.ReleasesUrl
+
+	if t.AlpineArch != nil && len(*t.AlpineArch) > 0 {
+		t.Arch = nil
+		for _, arch := range *t.AlpineArch {
+			t.Arch = append(t.Arch, AlpineArchFromPlatform(arch))
+		}
+	}
+	if t.AlpineMirrors != nil && len(*t.AlpineMirrors) > 0 {
+		t.Mirrors = *t.AlpineMirrors
+	}
+	if t.AlpineSecdbMirrors != nil && len(*t.AlpineSecdbMirrors) > 0 {
+		t.SecdbMirrors = *t.AlpineSecdbMirrors
+	}
+	if t.AlpineReleasesUrl != nil && *t.AlpineReleasesUrl != "" && t.ReleasesUrl != "" {
+		t.ReleasesUrl = *t.AlpineReleasesUrl
+	}
+	if t.AlpineRetry != nil && *t.AlpineRetry >= 0 {
+		t.Retry = *t.AlpineRetry
+	}
+	if t.AlpineTimeout != nil && *t.AlpineTimeout > 0 {
+		t.Timeout = time.Duration(*t.AlpineTimeout) * time.Second
+	}
+
+	if alpineBranch != nil && len(*alpineBranch) > 0 && t.BranchRolling == "" {
 		t.Branch = *alpineBranch
 	}
 
@@ -132,18 +292,27 @@ func (t *TypeDbAlpine) Connect() Idb {
 		}
 
 		if t.Base.Err == nil {
-			t.Db, t.Base.Err = gorm.Open(
-				sqlite.Open(t.FileDb),
-				&gorm.Config{
-					QueryFields: true,
-					Logger:      logger.Default.LogMode(logger.Silent),
-				},
-			)
-			if t.Base.Err != nil {
-				t.Base.Err = errors.New("cannot open " + t.FileDb)
+			t.dbFileLegacy()
+		}
+
+		if t.Base.Err == nil {
+			t.Db, t.Base.Err = dbOpen(t.FileDb)
+		}
+
+		// Upgrade schema in place
+		if t.Base.Err == nil {
+			var version int
+			version, t.Base.Err = dbMigrate(t.Db)
+			if t.Base.Err == nil && version < dbSchemaVersion() {
+				ezlog.Debug().N(prefix).N("schema").M(strconv.Itoa(version) + " -> " + strconv.Itoa(dbSchemaVersion())).Out()
 			}
 		}
 
+		// Full-text index is created once the program is built with FTS5
+		if t.Base.Err == nil {
+			t.Base.Err = ftsCreate(t.Db)
+		}
+
 		ezlog.Debug().N(prefix).TxtEnd().Out()
 	}
 	return t
@@ -179,30 +348,77 @@ func (t *TypeDbAlpine) Dump(yes bool) Idb {
 	return t
 }
 
-// Return immediately on error
+// Update
+//   - Return immediately on error
+//   - Only changed indexes are downloaded and replaced
 func (t *TypeDbAlpine) Update() Idb {
 	prefix := t.MyType + ".Update"
 	if t.CheckErrInit(prefix) {
 		ezlog.Debug().N(prefix).TxtStart().Out()
-		t.Base.Err = os.RemoveAll(t.DirDb) // Delete first
-		if t.Base.Err == nil {
-			t.Connect()
-		}
-		if t.Base.Err == nil {
-			t.Db.AutoMigrate(&TypeDbAlpineRecord{})
-		}
-		if t.Base.Err == nil {
-			t.Base.Err = t.idxUpdate()
-		}
+		t.stageUpdate(func() []string { return t.idxSync(t.idxEach, t.idxFetch) })
 		ezlog.Debug().N(prefix).TxtEnd().Out()
 	}
 	return t
 }
 
-// Search
-//   - Output result to stdout
+// Run [idxUpdate] against a staged copy of the database
+//   - Staged database is swapped in only if [idxUpdate] return no failed index
+func (t *TypeDbAlpine) stageUpdate(idxUpdate func() (failed []string)) {
+	prefix := t.MyType + ".stageUpdate"
+	var (
+		dbLive    *gorm.DB
+		failed    []string
+		fileStage = t.FileDb + ".stage"
+	)
+	if t.Db == nil {
+		t.Connect()
+	}
+	// Check once here instead of failing once per index
+	t.verify = t.VerifySign != nil && *t.VerifySign
+	if t.Base.Err == nil && t.verify && !rsaPubKeyAny(*t.DirKeys) {
+		t.verify = false
+		ezlog.Log().N(prefix).M("no trusted keys in " + *t.DirKeys + ", signature not verified, copy Alpine public keys(alpine-keys) there or set AlpineVerify to false").Out()
+	}
+	// Stage a copy of current database
+	if t.Base.Err == nil {
+		os.Remove(fileStage)
+		t.Base.Err = t.Db.Exec("VACUUM INTO ?", fileStage).Error
+	}
+	if t.Base.Err == nil {
+		dbLive = t.Db
+		t.Db, t.Base.Err = dbOpen(fileStage)
+	}
+	if t.Base.Err == nil {
+		_, t.Base.Err = dbMigrate(t.Db)
+	}
+	if t.Base.Err == nil {
+		failed = idxUpdate()
+	}
+	// Swap in staged database only if all indexes are imported
+	if dbLive != nil {
+		dbClose(t.Db)
+		if t.Base.Err == nil && len(failed) == 0 {
+			dbClose(dbLive)
+			t.Base.Err = os.Rename(fileStage, t.FileDb)
+			t.Db = nil
+			if t.Base.Err == nil {
+				t.Connect()
+			}
+		} else {
+			t.Db = dbLive
+			os.Remove(fileStage)
+			if t.Base.Err == nil {
+				t.Base.Err = errs.New(prefix, "database unchanged, failed: "+strings.Join(failed, ", "))
+			}
+		}
+	}
+}
+
+// Search packages by name [pkg]
+//   - [option] select match mode(substring, exact, regex), filters and newest version only
+//   - Return rows of `SearchColumns`
 //   - Return immediately on error
-func (t *TypeDbAlpine) Search(pkg string, exact bool) *[]*[]string {
+func (t *TypeDbAlpine) Search(pkg string, option *TypeDbSearchOption) *[]*[]string {
 	prefix := t.MyType + ".Search"
 	var (
 		strArrArr []*[]string
@@ -212,23 +428,30 @@ func (t *TypeDbAlpine) Search(pkg string, exact bool) *[]*[]string {
 		if t.Db == nil {
 			t.Base.Err = errors.New("database not connected")
 		}
-		var rows []TypeDbAlpineRecord
+		var (
+			re   *regexp.Regexp
+			rows []TypeDbAlpineRecord
+		)
+		if t.Base.Err == nil && option.Regex {
+			re, t.Base.Err = regexp.Compile(pkg)
+		}
 		if t.Base.Err == nil {
 			result := t.Db.
 				Unscoped().
-				Select([]string{"Pkg", "Ver", "Branch", "Repo", "Arch"})
-			if exact {
-				result = result.Where(map[string]interface{}{"Pkg": pkg})
-			} else {
-				result = result.Where("Pkg LIKE ?", "%"+pkg+"%")
-			}
-			result = result.Find(&rows)
+				Select([]string{"Pkg", "Ver", "Branch", "Repo", "Arch", "Desc"})
+			result = option.where(result, "type_db_alpine_records", "pkg", pkg)
+			result = result.Order("branch, repo, arch, pkg").Find(&rows)
 			t.Base.Err = result.Error
 		}
 		if t.Base.Err == nil {
+			if re != nil {
+				rows = slices.DeleteFunc(rows, func(r TypeDbAlpineRecord) bool { return !re.MatchString(r.Pkg) })
+			}
+			if option.Latest {
+				rows = t.searchLatest(rows)
+			}
 			for _, r := range rows {
-				// ezlog.Log().M(r.Pkg).M(r.Ver).M(r.Repo).M(r.Branch).M(r.Arch).Out()
-				strArr := []string{r.Pkg, r.Ver, r.Repo, r.Branch, r.Arch}
+				strArr := []string{r.Pkg, r.Ver, r.Repo, r.Branch, r.Arch, r.Desc}
 				strArrArr = append(strArrArr, &strArr)
 			}
 		}
@@ -237,127 +460,434 @@ func (t *TypeDbAlpine) Search(pkg string, exact bool) *[]*[]string {
 	return &strArrArr
 }
 
-func (t *TypeDbAlpine) VerGet(pkg string, branch, repo string) (ver *string) {
+// ArchGet return architectures in database
+func (t *TypeDbAlpine) ArchGet() []string { return t.Arch }
+
+// ArchFromPlatform return Alpine architecture of Docker [platform]
+func (t *TypeDbAlpine) ArchFromPlatform(platform string) string {
+	return AlpineArchFromPlatform(platform)
+}
+
+// BranchFromImage return branch of Docker image [tag], eg. "edge" -> "edge", "3.20.3" -> "v3.20", "latest" -> "latest-stable"
+//   - [image] is not used
+//   - Return `BranchRolling` if set, eg. Wolfi "latest" -> "rolling"
+//   - Other tags are returned as is
+func (t *TypeDbAlpine) BranchFromImage(image, tag string) string {
+	if t.BranchRolling != "" {
+		return t.BranchRolling
+	}
+	if tag == "latest" {
+		return "latest-stable"
+	}
+	if parts := strings.Split(tag, "."); len(parts) >= 2 && isDigits(parts[0]) && isDigits(parts[1]) {
+		return "v" + parts[0] + "." + parts[1]
+	}
+	return tag
+}
+
+// Return true if [s] is not empty and all digits
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// RepoGet return repositories checked by default, eg. Alpine "testing" is not included
+func (t *TypeDbAlpine) RepoGet() []string { return t.RepoDefault }
+
+// PkgVerSep return separator of package name and version in install command, eg. "apk add pkg=ver"
+func (t *TypeDbAlpine) PkgVerSep() string { return "=" }
+
+// VerNewer return true if [v1] > [v2]
+func (t *TypeDbAlpine) VerNewer(v1, v2 string) bool { return t.verNewer(v1, v2) }
+
+// VerGet return newest version of [pkg] in [branch]/[repo]/[arch]
+//   - Wolfi index keep all versions of a package
+//   - Return empty string if not found
+func (t *TypeDbAlpine) VerGet(pkg string, branch, repo, arch string) (ver *string) {
+	return t.verGetNewest(pkg, branch, repo, arch, t.verNewer)
+}
+
+// Return newest version of [pkg] in [branch]/[repo]/[arch] compared by [verNewer]
+//   - For index which can have more than one version of a package
+//   - Return empty string if not found
+func (t *TypeDbAlpine) verGetNewest(pkg string, branch, repo, arch string, verNewer func(v1, v2 string) bool) (ver *string) {
 	prefix := t.MyType + ".VerGet"
-	var row TypeDbAlpineRecord
+	var vers []string
+	ver = new(string)
 	if t.CheckErrInit(prefix) {
 		ezlog.Debug().N(prefix).TxtStart().Out()
 		if t.Db == nil {
 			t.Base.Err = errors.New("database not connected")
 		}
 		if t.Base.Err == nil {
-			result := t.Db.
-				Unscoped().
+			t.Base.Err = t.Db.
+				Model(&TypeDbAlpineRecord{}).
 				Where(map[string]interface{}{
 					"Branch": branch,
 					"Repo":   repo,
-					"Arch":   t.Arch,
+					"Arch":   arch,
 					"Pkg":    pkg,
 				}).
-				Find(&row)
+				Pluck("ver", &vers).Error
+		}
+		for _, v := range vers {
+			if verNewer(v, *ver) {
+				*ver = v
+			}
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return ver
+}
+
+// Info return full record of [pkg] [ver] in [branch]/[repo]/[arch]
+//   - Empty [arch] is any database architecture, empty [ver] is any version
+//   - Child tables(Depends, Provides, InstallIf) are loaded
+//   - Return nil if not found
+func (t *TypeDbAlpine) Info(pkg string, branch, repo, arch, ver string) (record *TypeDbAlpineRecord) {
+	prefix := t.MyType + ".Info"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		if t.Base.Err == nil {
+			var (
+				rows  []TypeDbAlpineRecord
+				where = map[string]interface{}{
+					"Branch": branch,
+					"Repo":   repo,
+					"Arch":   t.Arch,
+					"Pkg":    pkg,
+				}
+			)
+			if arch != "" {
+				where["Arch"] = arch
+			}
+			if ver != "" {
+				where["Ver"] = ver
+			}
+			result := t.Db.
+				Preload("Depends").
+				Preload("Provides").
+				Preload("InstallIf").
+				Where(where).
+				Limit(1).
+				Find(&rows)
 			t.Base.Err = result.Error
+			if len(rows) > 0 {
+				record = &rows[0]
+			}
 		}
 		ezlog.Debug().N(prefix).TxtEnd().Out()
 	}
-	return &row.Ver
+	return record
+}
+
+// Verify signature of cached APKINDEX.tar.gz
+//   - Return one row per index: branch, repo, arch, key, result
+//   - Index not in cache is skipped
+func (t *TypeDbAlpine) Verify() *[]*[]string {
+	prefix := t.MyType + ".Verify"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.idxEach(func(branch, repo, arch string) {
+			var (
+				data   []byte
+				err    error
+				idx    *TypeApkIndexTgz
+				result = "ok"
+			)
+			data, err = os.ReadFile(t.idxFile(branch, repo, arch))
+			if os.IsNotExist(err) {
+				return
+			}
+			if err == nil {
+				idx, err = apkIndexTgzRead(bytes.NewReader(data), t.FileIndex, branch, repo, arch)
+			}
+			if err == nil {
+				err = apkIndexVerify(data, idx, *t.DirKeys)
+			}
+			if err != nil {
+				result = err.Error()
+				t.Base.Err = errs.New(prefix, "verification failed")
+			}
+			signName := ""
+			if idx != nil {
+				signName = idx.SignName
+			}
+			strArr := []string{branch, repo, arch, signName, result}
+			strArrArr = append(strArrArr, &strArr)
+		})
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
+
+// Result of one index fetched by worker
+type idxResult struct {
+	index    *TypeDbAlpineIndex
+	parsed   bool // content changed, `rows` replace those in database
+	rows     []TypeDbAlpineRecord
+	desc     string // index description
+	signName string // index signing key
+	hash     string
+	modified bool
+	err      error
 }
 
-// Wrapper for Alpine APKINDEX download and database create/update
-func (t *TypeDbAlpine) idxUpdate() (err error) {
+// Wrapper for index download and database create/update
+//   - [each] iterate all indexes, [fetch] download, parse and verify one index
+//   - [fetch] run in `Concurrency` workers
+//   - Database write is done in caller goroutine only
+//   - Return name(branch/repo/arch) of failed indexes
+func (t *TypeDbAlpine) idxUpdate(each func(f func(branch, repo, arch string)), fetch func(index *TypeDbAlpineIndex) *idxResult) (failed []string) {
 	prefix := t.MyType + ".idxUpdate"
 	ezlog.Debug().N(prefix).TxtStart().Out()
-	for _, branch := range t.Branch {
-		for _, repo := range t.Repository {
-			for _, arch := range t.Arch {
-				// stable branches don't have "testing"
-				stable := branch == "latest-stable" || strings.ToLower(branch)[0] == 'v'
-				if !(stable && repo == "testing") {
-					// Download APKINDEX.tar.gz
-					err = t.idxDownload(branch, repo, arch)
-					// Update database
-					if err == nil {
-						err = t.idx2db(branch, repo, arch)
-					}
-					errs.Queue(prefix, err)
-				}
+
+	var (
+		concurrency = max(1, *t.Concurrency)
+		now         = time.Now()
+		indexes     []*TypeDbAlpineIndex
+		jobs        = make(chan *TypeDbAlpineIndex)
+		results     = make(chan *idxResult)
+		wg          sync.WaitGroup
+	)
+
+	t.client = downloadClient(t.Timeout)
+
+	each(func(branch, repo, arch string) {
+		index, err := idxGet(t.Db, branch, repo, arch)
+		if err == nil {
+			indexes = append(indexes, index)
+		} else {
+			failed = append(failed, branch+"/"+repo+"/"+arch)
+			errs.Queue(prefix, err)
+		}
+	})
+
+	// Workers, must not use ezlog, errs or database
+	for range min(concurrency, len(indexes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results <- fetch(index)
 			}
+		}()
+	}
+	go func() {
+		for _, index := range indexes {
+			jobs <- index
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	for res := range results {
+		err := res.err
+		ezlog.Debug().N(prefix).N(res.index.Name()).N("modified").M(res.modified).Out()
+		if err == nil {
+			res.index.FetchedAt = now
 		}
+		if err == nil && res.modified {
+			err = t.idx2db(res)
+		}
+		if err == nil && !res.modified {
+			err = t.Db.Model(res.index).UpdateColumn("fetched_at", now).Error
+		}
+		if err == nil {
+			err = histUpdate(t.Db, res.index, now)
+		}
+		if err != nil {
+			failed = append(failed, res.index.Name())
+		}
+		errs.Queue(prefix, err)
 	}
+	slices.Sort(failed)
 
 	ezlog.Debug().N(prefix).TxtEnd().Out()
-	return err
+	return failed
 }
 
-func (t *TypeDbAlpine) idxDownload(branch string, repo string, arch string) (err error) {
-	prefix := t.MyType + ".idxDownload"
-	ezlog.Debug().N(prefix).TxtStart().Out()
+// Update indexes of [each] with [fetch] like idxUpdate(), then remove indexes not in [each]
+//   - Removed indexes are branches, repositories or architectures no longer configured,
+//     branches added from projects are downloaded again when referenced, see BranchAdd()
+//   - Nothing is removed if any index failed
+func (t *TypeDbAlpine) idxSync(each func(f func(branch, repo, arch string)), fetch func(index *TypeDbAlpineIndex) *idxResult) (failed []string) {
+	failed = t.idxUpdate(each, fetch)
+	if len(failed) == 0 {
+		failed = t.idxPrune(each)
+	}
+	return failed
+}
 
-	// Prepare download URL
-	urlApkIndex, err := url.JoinPath(t.UrlBase, branch, repo, arch, t.FileIndex+extTgz)
+// Remove indexes not in [each] with their records, child rows and full-text index
+//   - Return failed index names, error is queued in errs
+func (t *TypeDbAlpine) idxPrune(each func(f func(branch, repo, arch string))) (failed []string) {
+	prefix := t.MyType + ".idxPrune"
+	var (
+		indexes []TypeDbAlpineIndex
+		keep    = map[string]bool{}
+	)
+	each(func(branch, repo, arch string) {
+		keep[(&TypeDbAlpineIndex{Branch: branch, Repo: repo, Arch: arch}).Name()] = true
+	})
+	err := t.Db.Find(&indexes).Error
+	if err != nil {
+		failed = append(failed, "prune")
+	}
+	errs.Queue(prefix, err)
+	for _, index := range indexes {
+		if keep[index.Name()] {
+			continue
+		}
+		ezlog.Debug().N(prefix).M(index.Name()).Out()
+		if err := idxDelete(t.Db, &index); err != nil {
+			failed = append(failed, index.Name())
+			errs.Queue(prefix, err)
+		}
+	}
+	return failed
+}
 
-	// Create directory
-	dirArch := t.idxDir(branch, repo, arch)
-	if err == nil {
-		err = os.MkdirAll(dirArch, os.ModePerm)
-		errs.Queue(prefix, err)
+// Download, read and verify APKINDEX.tar.gz of [index]
+//   - Run in worker, must not use ezlog, errs or database
+func (t *TypeDbAlpine) idxFetch(index *TypeDbAlpineIndex) (res *idxResult) {
+	var (
+		data []byte
+		idx  *TypeApkIndexTgz
+	)
+	res = &idxResult{index: index}
+	indexPath := strings.NewReplacer("{branch}", index.Branch, "{repo}", index.Repo, "{arch}", index.Arch).Replace(t.IndexPath)
+	res.modified, res.err = t.idxDownload(index, t.Mirrors, []string{indexPath, t.FileIndex + extTgz}, t.idxFile(index.Branch, index.Repo, index.Arch))
+	// Read APKINDEX.tar.gz
+	if res.err == nil && res.modified {
+		data, res.err = os.ReadFile(t.idxFile(index.Branch, index.Repo, index.Arch))
+	}
+	// Parse only if content changed
+	if res.err == nil && res.modified {
+		sum := sha256.Sum256(data)
+		res.hash = hex.EncodeToString(sum[:])
+		if res.hash != index.Hash {
+			idx, res.err = apkIndexTgzRead(bytes.NewReader(data), t.FileIndex, index.Branch, index.Repo, index.Arch)
+		}
+	}
+	// Refuse to import if signature verification failed
+	if res.err == nil && idx != nil && t.verify {
+		res.err = apkIndexVerify(data, idx, *t.DirKeys)
+	}
+	if res.err == nil && idx != nil {
+		res.parsed = true
+		res.rows = idx.Rows
+		res.desc = idx.Description
+		res.signName = idx.SignName
 	}
+	if res.err != nil {
+		res.err = errors.New(index.Name() + ": " + res.err.Error())
+	}
+	return res
+}
 
-	// Download APKINDEX.tar.gz
-	filepathApkindex := path.Join(dirArch, t.FileIndex)
-	filepathApkindexTgz := filepathApkindex + extTgz
-	if err == nil {
-		err = download(urlApkIndex, filepathApkindexTgz)
+// Download index file of [index] into [filepathIdx]
+//   - URL is [urlPath] joined to each of [mirrors], tried in order
+//   - Conditional request if [index] was imported and cached, only to the mirror which served it
+//   - [modified] is false if server return 304 Not Modified
+//   - Run in worker, must not use ezlog, errs or database
+func (t *TypeDbAlpine) idxDownload(index *TypeDbAlpineIndex, mirrors, urlPath []string, filepathIdx string) (modified bool, err error) {
+	var (
+		etag         string
+		lastModified string
+	)
+
+	// Create directory
+	err = os.MkdirAll(path.Dir(filepathIdx), os.ModePerm)
+
+	// Only use conditional request if index is in both database and cache
+	if index.Hash != "" && file.IsRegularFile(filepathIdx) {
+		etag = index.ETag
+		lastModified = index.LastModified
 	}
-	// Decompress APKINDEX.tar.gz
+
+	// Download index, try mirrors in order
 	if err == nil {
-		err = untar(dirArch, filepathApkindexTgz)
+		var errMirrors []string
+		for _, mirror := range mirrors {
+			var (
+				res      *downloadResult
+				urlIndex string
+			)
+			urlIndex, err = url.JoinPath(mirror, urlPath...)
+			if err == nil {
+				// ETag and Last-Modified are mirror specific, mirrors sync at different time
+				etagMirror, lastModifiedMirror := "", ""
+				if mirror == index.Mirror {
+					etagMirror, lastModifiedMirror = etag, lastModified
+				}
+				res, err = downloadRetry(t.client, urlIndex, filepathIdx, etagMirror, lastModifiedMirror, t.Retry)
+			}
+			if err == nil {
+				modified = res.Modified
+				if modified {
+					index.ETag = res.ETag
+					index.LastModified = res.LastModified
+				}
+				index.Mirror = mirror
+				break
+			}
+			errMirrors = append(errMirrors, err.Error())
+		}
+		if err != nil {
+			err = errors.New("all mirrors failed: " + strings.Join(errMirrors, "; "))
+		}
 	}
 
-	ezlog.Debug().N(prefix).TxtEnd().Out()
-	return err
+	return modified, err
 }
 
-func (t *TypeDbAlpine) idx2db(branch string, repo string, arch string) (err error) {
+// Import fetched index [res] into database
+//   - Rows are replaced in one transaction if content changed
+//   - Otherwise only new ETag/Last-Modified are saved
+func (t *TypeDbAlpine) idx2db(res *idxResult) (err error) {
 	prefix := t.MyType + ".idx2db"
 	ezlog.Debug().N(prefix).TxtStart().Out()
 
-	dirArch := t.idxDir(branch, repo, arch)
-	filepathApkIndex := path.Join(dirArch, t.FileIndex)
-	ezlog.Debug().N(prefix).M(filepathApkIndex).Out()
+	index := res.index
+	if !res.parsed {
+		err = t.Db.Save(index).Error
+	} else {
+		ezlog.Debug().N(prefix).N(index.Name()).M(res.desc).Out()
+		index.Description = res.desc
+		index.Hash = res.hash
+		index.SignName = res.signName
+		err = idxReplace(t.Db, index, res.rows)
+	}
 
-	var rows array.Array[TypeDbAlpineRecord]
+	ezlog.Debug().N(prefix).TxtEnd().Out()
+	return err
+}
 
-	// Read APKINDEX file
-	byteRead, err := os.ReadFile(filepathApkIndex)
+// Call [f] for each branch, repository and architecture combination
+//   - stable branches don't have "testing"
+func (t *TypeDbAlpine) idxEach(f func(branch, repo, arch string)) {
+	t.idxEachBranch(t.Branch, f)
+}
 
-	if err == nil {
-		// Prepare DB rows
-		lines := strings.Split(string(byteRead), "\n")
-		var recordP *TypeDbAlpineRecord
-		for _, l := range lines {
-			if len(l) > 0 {
-				switch l[0] {
-				case 'P':
-					recordP = &TypeDbAlpineRecord{
-						Branch: branch,
-						Repo:   repo,
-						Arch:   arch,
-						Pkg:    l[2:],
-					}
-				case 'V':
-					recordP.Ver = l[2:]
-					rows.Add(*recordP)
+// Call [f] for each repository and architecture combination of [branches]
+func (t *TypeDbAlpine) idxEachBranch(branches []string, f func(branch, repo, arch string)) {
+	for _, branch := range branches {
+		for _, repo := range t.Repository {
+			for _, arch := range t.Arch {
+				stable := branch == "latest-stable" || strings.ToLower(branch)[0] == 'v'
+				if !(stable && repo == "testing") {
+					f(branch, repo, arch)
 				}
 			}
 		}
-		// Batch insert into DB
-		result := t.Db.CreateInBatches(rows, 1000)
-		err = result.Error
 	}
-
-	errs.Queue(prefix, err)
-	ezlog.Debug().N(prefix).TxtEnd().Out()
-	return err
 }
 
 // Calculate(join) APKINDEX directory path base on `repo`, `branch`, `arch` and
@@ -365,40 +895,52 @@ func (t *TypeDbAlpine) idxDir(branch string, repo string, arch string) string {
 	return path.Join(t.DirDb, branch, repo, arch)
 }
 
-// URL download to file
-func download(url string, filepath string) (err error) {
-	prefix := "download"
-	ezlog.Debug().N(prefix).TxtStart().Out()
-	ezlog.Debug().Nl(prefix).T().Ml(url).T().T().M(filepath)
+// Calculate(join) APKINDEX.tar.gz file path base on `repo`, `branch`, `arch`
+func (t *TypeDbAlpine) idxFile(branch string, repo string, arch string) string {
+	return path.Join(t.idxDir(branch, repo, arch), t.FileIndex+extTgz)
+}
 
-	var res *http.Response
-	out, err := os.Create(filepath)
-	if err == nil {
-		defer out.Close()
-		res, err = http.Get(url)
-		if res.Status[0:1] == "4" { // eg. 404
-			err = errors.New(url + " " + res.Status)
-		}
-	}
-	if err == nil {
-		defer res.Body.Close()
-		_, err = io.Copy(out, res.Body)
+// Open sqlite database [filepath]
+func dbOpen(filepath string) (db *gorm.DB, err error) {
+	db, err = gorm.Open(
+		sqlite.Open(filepath),
+		&gorm.Config{
+			CreateBatchSize: 1000, // also apply to child tables
+			QueryFields:     true,
+			Logger:          logger.Default.LogMode(logger.Silent),
+		},
+	)
+	if err != nil {
+		err = errors.New("cannot open " + filepath)
 	}
-	// errs.Queue(prefix, err)
-
-	ezlog.Debug().N(prefix).TxtEnd().Out()
-	return err
+	return db, err
 }
 
-// Use command line tar to untar
-func untar(dir string, filepath string) error {
-	prefix := "untar"
-	ezlog.Debug().N(prefix).TxtStart().Out()
-
-	opts := []string{"xf", filepath, "-C", dir}
-	myCmd := cmd.Run("tar", &opts, nil)
-	errs.Queue(prefix, myCmd.Err)
+// Move database of earlier versions, `<DirDbName>/.db`, to `FileDb` if `FileDb` does not exist
+//   - Only Alpine had a database before each distro got its own directory
+//   - APKINDEX cache is not moved, records are kept in the database
+func (t *TypeDbAlpine) dbFileLegacy() {
+	prefix := t.MyType + ".dbFileLegacy"
+	if t.Distro != DbAlpineDefault.Distro {
+		return
+	}
+	fileLegacy := path.Join(*t.DirCache, *t.DirDbName, ".db")
+	if _, err := os.Stat(fileLegacy); err != nil {
+		return
+	}
+	if _, err := os.Stat(t.FileDb); !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	ezlog.Debug().N(prefix).M(fileLegacy + " -> " + t.FileDb).Out()
+	t.Base.Err = os.Rename(fileLegacy, t.FileDb)
+}
 
-	ezlog.Debug().N(prefix).TxtEnd().Out()
-	return myCmd.Err
+// Close underlying connection of [db]
+func dbClose(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDb, err := db.DB(); err == nil {
+		sqlDb.Close()
+	}
 }
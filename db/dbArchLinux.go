@@ -0,0 +1,217 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/J-Siu/go-helper/v2/basestruct"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+var DbArchLinuxDefault = TypeDbAlpine{
+	FileIndex: ".db", // prefixed by repository name, eg. core.db
+	Mirrors:   []string{"https://geo.mirror.pkgbuild.com"},
+	Retry:     2,
+	Timeout:   60 * time.Second,
+
+	Distro:        "archlinux",
+	Branch:        []string{"rolling"},
+	BranchRolling: "rolling",
+	Repository:    []string{"core", "extra"},
+	RepoDefault:   []string{"core", "extra"},
+	Arch:          []string{"x86_64"},
+}
+
+type TypeDbArchLinuxProperty struct {
+	DirCache    *string `json:"DirCache"`    // Full path of cache directory
+	DirDbName   *string `json:"DirDbName"`   // Directory name, not full path, of database
+	Concurrency *int    `json:"Concurrency"` // Number of parallel index download
+
+	ArchLinuxMirrors *[]string `json:"ArchLinuxMirrors"` // Base URL of mirrors, in order of preference, use default if empty
+}
+
+// Arch Linux package database struct base on repository, read from pacman sync database(<repo>.db)
+//
+//   - Share database schema and queries with [TypeDbAlpine]
+//   - Arch Linux is rolling, there is only one branch
+//   - Only x86_64 is officially supported
+type TypeDbArchLinux struct {
+	*TypeDbAlpine
+	*TypeDbArchLinuxProperty
+}
+
+func (t *TypeDbArchLinux) New(property *TypeDbArchLinuxProperty) *TypeDbArchLinux {
+	t.TypeDbArchLinuxProperty = property
+	t.TypeDbAlpine = &TypeDbAlpine{
+		Base:     new(basestruct.Base),
+		verNewer: RpmVerNewer,
+		TypeDbAlpineProperty: &TypeDbAlpineProperty{
+			DirCache:    t.DirCache,
+			DirDbName:   t.DirDbName,
+			Concurrency: t.Concurrency,
+		},
+	}
+	t.Initialized = true
+	t.MyType = "TypeDbArchLinux"
+	prefix := t.MyType + ".init"
+	ezlog.Debug().N(prefix).TxtStart().Out()
+
+	t.setDefault()
+
+	t.DirDb = path.Join(*t.DirCache, *t.DirDbName, t.Distro)
+	t.FileDb = path.Join(t.DirDb, t.Distro+".db")
+
+	ezlog.Debug().N(prefix).Lm(t).Out()
+
+	ezlog.Debug().N(prefix).TxtEnd().Out()
+	return t
+}
+
+func (t *TypeDbArchLinux) setDefault() *TypeDbArchLinux {
+	def := &DbArchLinuxDefault
+	t.FileIndex = def.FileIndex
+	t.Mirrors = def.Mirrors
+	t.Retry = def.Retry
+	t.Timeout = def.Timeout
+	t.Distro = def.Distro
+	t.Branch = def.Branch
+	t.BranchRolling = def.BranchRolling
+	t.Repository = def.Repository
+	t.RepoDefault = def.RepoDefault
+	t.Arch = def.Arch
+
+	if t.ArchLinuxMirrors != nil && len(*t.ArchLinuxMirrors) > 0 {
+		t.Mirrors = *t.ArchLinuxMirrors
+	}
+	return t
+}
+
+func (t *TypeDbArchLinux) Connect() Idb {
+	t.TypeDbAlpine.Connect()
+	return t
+}
+
+func (t *TypeDbArchLinux) Dump(yes bool) Idb {
+	t.TypeDbAlpine.Dump(yes)
+	return t
+}
+
+// Update
+//   - Return immediately on error
+//   - Only changed <repo>.db are downloaded and replaced
+func (t *TypeDbArchLinux) Update() Idb {
+	prefix := t.MyType + ".Update"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.stageUpdate(func() []string { return t.idxSync(t.idxEach, t.idxFetch) })
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Return Arch Linux architecture of [platform], same as RPM, eg. "linux/amd64" -> "x86_64"
+func (t *TypeDbArchLinux) ArchFromPlatform(platform string) string {
+	if arch, ok := RpmPlatformArch[strings.ToLower(platform)]; ok {
+		return arch
+	}
+	return platform
+}
+
+// VerGet return newest version of [pkg] in [branch]/[repo]/[arch]
+//   - Return empty string if not found
+func (t *TypeDbArchLinux) VerGet(pkg string, branch, repo, arch string) (ver *string) {
+	return t.verGetNewest(pkg, branch, repo, arch, RpmVerNewer)
+}
+
+// VerNewer return true if [v1] > [v2], pacman vercmp is the same as RPM
+func (t *TypeDbArchLinux) VerNewer(v1, v2 string) bool { return RpmVerNewer(v1, v2) }
+
+// Verify is not supported, <repo>.db.sig is not checked
+func (t *TypeDbArchLinux) Verify() *[]*[]string {
+	prefix := t.MyType + ".Verify"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return &[]*[]string{}
+}
+
+// ReleaseUpdate is not supported, Arch Linux is rolling
+func (t *TypeDbArchLinux) ReleaseUpdate(file string) Idb {
+	prefix := t.MyType + ".ReleaseUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// SecdbUpdate is not supported, Arch Linux has no secdb
+func (t *TypeDbArchLinux) SecdbUpdate(files []string) Idb {
+	prefix := t.MyType + ".SecdbUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// Download, read and parse <repo>.db of [index]
+//   - Run in worker, must not use ezlog, errs or database
+func (t *TypeDbArchLinux) idxFetch(index *TypeDbAlpineIndex) (res *idxResult) {
+	var (
+		data    []byte
+		fileIdx = index.Repo + t.FileIndex
+	)
+	res = &idxResult{index: index}
+	res.modified, res.err = t.idxDownload(index, t.Mirrors, []string{index.Repo, "os", index.Arch, fileIdx}, t.idxFile(index.Branch, index.Repo, index.Arch))
+	if res.err == nil && res.modified {
+		data, res.err = os.ReadFile(t.idxFile(index.Branch, index.Repo, index.Arch))
+	}
+	// Parse only if content changed
+	if res.err == nil && res.modified {
+		sum := sha256.Sum256(data)
+		res.hash = hex.EncodeToString(sum[:])
+		if res.hash != index.Hash {
+			res.rows, res.err = alpmDbRead(bytes.NewReader(data), index.Branch, index.Repo, index.Arch)
+			res.parsed = res.err == nil
+			res.desc = t.Distro + " " + index.Repo
+		}
+	}
+	if res.err != nil {
+		res.err = errors.New(index.Name() + ": " + res.err.Error())
+	}
+	return res
+}
+
+// Calculate(join) <repo>.db file path base on `branch`, `repo`, `arch`
+func (t *TypeDbArchLinux) idxFile(branch string, repo string, arch string) string {
+	return path.Join(t.idxDir(branch, repo, arch), repo+t.FileIndex)
+}
@@ -0,0 +1,369 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/basestruct"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+// Default settings of Debian based distributions, key is distro name
+var DbDebianDefault = map[string]*TypeDbDebianDistro{
+	"debian": {
+		FileIndex:       "Packages.xz",
+		Mirrors:         []string{"http://deb.debian.org/debian"},
+		MirrorsSecurity: []string{"http://deb.debian.org/debian-security"},
+		Branch:          []string{"bookworm", "trixie"},
+		Pocket:          []string{"", "-updates", "-security"},
+		Repository:      []string{"main"},
+		Arch:            []string{"amd64", "arm64"},
+		Tags: map[string]string{
+			"11":        "bullseye",
+			"12":        "bookworm",
+			"13":        "trixie",
+			"14":        "forky",
+			"latest":    "trixie",
+			"stable":    "trixie",
+			"oldstable": "bookworm",
+			"testing":   "forky",
+		},
+	},
+	"ubuntu": {
+		FileIndex:    "Packages.xz",
+		Mirrors:      []string{"http://archive.ubuntu.com/ubuntu"},
+		MirrorsPorts: []string{"http://ports.ubuntu.com/ubuntu-ports"},
+		Branch:       []string{"jammy", "noble"},
+		Pocket:       []string{"", "-updates", "-security"},
+		Repository:   []string{"main", "universe"},
+		Arch:         []string{"amd64", "arm64"},
+		Tags: map[string]string{
+			"20.04":  "focal",
+			"22.04":  "jammy",
+			"24.04":  "noble",
+			"24.10":  "oracular",
+			"25.04":  "plucky",
+			"latest": "noble",
+		},
+	},
+}
+
+// Docker platform and Alpine architecture to Debian architecture
+var DebianPlatformArch = map[string]string{
+	"linux/386":      "i386",
+	"linux/amd64":    "amd64",
+	"linux/arm/v5":   "armel",
+	"linux/arm/v7":   "armhf",
+	"linux/arm64":    "arm64",
+	"linux/arm64/v8": "arm64",
+	"linux/ppc64le":  "ppc64el",
+	"linux/riscv64":  "riscv64",
+	"linux/s390x":    "s390x",
+	"aarch64":        "arm64",
+	"armv7":          "armhf",
+	"ppc64le":        "ppc64el",
+	"x86":            "i386",
+	"x86_64":         "amd64",
+}
+
+// Architectures served by `Mirrors`, others are served by `MirrorsPorts` if set
+var DebianArchMain = []string{"amd64", "i386"}
+
+// Distro specific settings of Debian based distributions
+type TypeDbDebianDistro struct {
+	FileIndex       string            // Packages.xz or Packages.gz
+	Mirrors         []string          // Base URL of mirrors, in order of preference
+	MirrorsPorts    []string          // Base URL of mirrors for architectures not in `DebianArchMain`, eg. Ubuntu ports
+	MirrorsSecurity []string          // Base URL of mirrors for "-security" pocket if not in `Mirrors`, eg. Debian debian-security
+	Branch          []string          // Suites(codenames)
+	Pocket          []string          // Suite suffix, eg. "-updates", "" for the release itself
+	Repository      []string          // Components
+	Arch            []string          // Architectures
+	Tags            map[string]string // Docker image tag to codename, eg. "12" -> "bookworm"
+}
+
+type TypeDbDebianProperty struct {
+	DirCache    *string `json:"DirCache"`    // Full path of cache directory
+	DirDbName   *string `json:"DirDbName"`   // Directory name, not full path, of database
+	Concurrency *int    `json:"Concurrency"` // Number of parallel index download
+	DistroName  *string `json:"DistroName"`  // Key of `DbDebianDefault`, eg. debian, ubuntu
+
+	DebianArch    *[]string `json:"DebianArch"`    // Architecture list, Docker platform or Alpine architecture is converted, use default if empty
+	DebianBranch  *[]string `json:"DebianBranch"`  // Suite(codename) list, use default if empty
+	DebianMirrors *[]string `json:"DebianMirrors"` // Base URL of mirrors, in order of preference, use default if empty
+
+	DebianSecurityMirrors *[]string `json:"DebianSecurityMirrors"` // Base URL of mirrors of "-security" pocket, use default if empty
+}
+
+// Debian/Ubuntu package database struct base on suite, component and arch
+//
+//   - Share database schema and queries with [TypeDbAlpine]
+//   - `Branch` is suite codename, `Repo` is component, prefixed by pocket if any, eg. "updates/main"
+type TypeDbDebian struct {
+	*TypeDbAlpine
+	*TypeDbDebianProperty
+
+	MirrorsPorts    []string
+	MirrorsSecurity []string
+	Pocket          []string
+	Tags            map[string]string
+}
+
+func (t *TypeDbDebian) New(property *TypeDbDebianProperty) *TypeDbDebian {
+	t.TypeDbDebianProperty = property
+	t.TypeDbAlpine = &TypeDbAlpine{
+		Base:     new(basestruct.Base),
+		verNewer: DebVerNewer,
+		TypeDbAlpineProperty: &TypeDbAlpineProperty{
+			DirCache:    t.DirCache,
+			DirDbName:   t.DirDbName,
+			Concurrency: t.Concurrency,
+		},
+	}
+	t.Initialized = true
+	t.MyType = "TypeDbDebian"
+	prefix := t.MyType + ".init"
+	ezlog.Debug().N(prefix).TxtStart().Out()
+
+	t.setDefault()
+
+	t.DirDb = path.Join(*t.DirCache, *t.DirDbName, t.Distro)
+	t.FileDb = path.Join(t.DirDb, t.Distro+".db")
+
+	ezlog.Debug().N(prefix).Lm(t).Out()
+
+	ezlog.Debug().N(prefix).TxtEnd().Out()
+	return t
+}
+
+func (t *TypeDbDebian) setDefault() *TypeDbDebian {
+	prefix := t.MyType + ".setDefault"
+	t.Distro = strings.ToLower(*t.DistroName)
+	def, ok := DbDebianDefault[t.Distro]
+	if !ok {
+		t.Base.Err = errors.New(prefix + ": " + t.Distro + " not supported")
+		def = DbDebianDefault["debian"]
+	}
+	t.FileIndex = def.FileIndex
+	t.Mirrors = def.Mirrors
+	t.MirrorsPorts = def.MirrorsPorts
+	t.MirrorsSecurity = def.MirrorsSecurity
+	t.Branch = def.Branch
+	t.Pocket = def.Pocket
+	t.Repository = def.Repository
+	t.Arch = def.Arch
+	t.Tags = def.Tags
+	t.Retry = DbAlpineDefault.Retry
+	t.Timeout = 2 * DbAlpineDefault.Timeout // Packages is much larger than APKINDEX
+
+	if t.DebianArch != nil && len(*t.DebianArch) > 0 {
+		t.Arch = nil
+		for _, arch := range *t.DebianArch {
+			t.Arch = append(t.Arch, t.ArchFromPlatform(arch))
+		}
+	}
+	if t.DebianBranch != nil && len(*t.DebianBranch) > 0 {
+		t.Branch = *t.DebianBranch
+	}
+	if t.DebianMirrors != nil && len(*t.DebianMirrors) > 0 {
+		t.Mirrors = *t.DebianMirrors
+	}
+	if t.DebianSecurityMirrors != nil && len(*t.DebianSecurityMirrors) > 0 {
+		t.MirrorsSecurity = *t.DebianSecurityMirrors
+	}
+	return t
+}
+
+func (t *TypeDbDebian) Connect() Idb {
+	t.TypeDbAlpine.Connect()
+	return t
+}
+
+func (t *TypeDbDebian) Dump(yes bool) Idb {
+	t.TypeDbAlpine.Dump(yes)
+	return t
+}
+
+// Update
+//   - Return immediately on error
+//   - Only changed Packages are downloaded and replaced
+func (t *TypeDbDebian) Update() Idb {
+	prefix := t.MyType + ".Update"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.stageUpdate(func() []string { return t.idxSync(t.idxEach, t.idxFetch) })
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Return Debian architecture of [platform], eg. "linux/amd64" -> "amd64"
+//   - Alpine architecture is also converted, eg. "x86_64" -> "amd64"
+//   - [platform] is returned as is if not known
+func (t *TypeDbDebian) ArchFromPlatform(platform string) string {
+	if arch, ok := DebianPlatformArch[strings.ToLower(platform)]; ok {
+		return arch
+	}
+	return platform
+}
+
+// Return codename of Docker image [tag], [image] is not used
+//   - Variant suffix is removed, eg. "bookworm-slim" -> "bookworm"
+//   - Version is mapped with `Tags`, eg. "12.7-slim" -> "bookworm", "22.04" -> "jammy"
+func (t *TypeDbDebian) BranchFromImage(image, tag string) string {
+	branch, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if codename, ok := t.Tags[branch]; ok {
+		return codename
+	}
+	if major, _, found := strings.Cut(branch, "."); found {
+		if codename, ok := t.Tags[major]; ok {
+			return codename
+		}
+	}
+	return branch
+}
+
+// Return all components of all pockets, eg. "main", "updates/main"
+func (t *TypeDbDebian) RepoGet() (repos []string) {
+	for _, pocket := range t.Pocket {
+		for _, component := range t.Repository {
+			repos = append(repos, debRepoJoin(pocket, component))
+		}
+	}
+	return repos
+}
+
+// VerGet return newest version of [pkg] in [branch]/[repo]/[arch]
+//   - Return empty string if not found
+func (t *TypeDbDebian) VerGet(pkg string, branch, repo, arch string) (ver *string) {
+	return t.verGetNewest(pkg, branch, repo, arch, DebVerNewer)
+}
+
+// VerNewer return true if [v1] > [v2], Debian version comparison
+func (t *TypeDbDebian) VerNewer(v1, v2 string) bool { return DebVerNewer(v1, v2) }
+
+// Verify is not supported, Release signature is not checked
+func (t *TypeDbDebian) Verify() *[]*[]string {
+	prefix := t.MyType + ".Verify"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return &[]*[]string{}
+}
+
+// ReleaseUpdate is not supported, no release metadata is imported
+func (t *TypeDbDebian) ReleaseUpdate(file string) Idb {
+	prefix := t.MyType + ".ReleaseUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// SecdbUpdate is not supported, Debian/Ubuntu security tracker is not imported
+func (t *TypeDbDebian) SecdbUpdate(files []string) Idb {
+	prefix := t.MyType + ".SecdbUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// Download, read and parse Packages of [index]
+//   - Run in worker, must not use ezlog, errs or database
+func (t *TypeDbDebian) idxFetch(index *TypeDbAlpineIndex) (res *idxResult) {
+	var (
+		data              []byte
+		mirrors           = t.Mirrors
+		pocket, component = debRepoSplit(index.Repo)
+		urlPath           = []string{"dists", index.Branch + pocket, component, "binary-" + index.Arch, t.FileIndex}
+	)
+	if len(t.MirrorsPorts) > 0 && !slices.Contains(DebianArchMain, index.Arch) {
+		mirrors = t.MirrorsPorts
+	}
+	if len(t.MirrorsSecurity) > 0 && pocket == "-security" {
+		mirrors = t.MirrorsSecurity
+	}
+	res = &idxResult{index: index}
+	res.modified, res.err = t.idxDownload(index, mirrors, urlPath, t.idxFile(index.Branch, index.Repo, index.Arch))
+	if res.err == nil && res.modified {
+		data, res.err = os.ReadFile(t.idxFile(index.Branch, index.Repo, index.Arch))
+	}
+	// Parse only if content changed
+	if res.err == nil && res.modified {
+		sum := sha256.Sum256(data)
+		res.hash = hex.EncodeToString(sum[:])
+		if res.hash != index.Hash {
+			res.rows, res.err = debPackagesRead(bytes.NewReader(data), index.Branch, index.Repo, index.Arch)
+			res.parsed = res.err == nil
+			res.desc = t.Distro + " " + index.Branch + pocket + " " + component
+		}
+	}
+	if res.err != nil {
+		res.err = errors.New(index.Name() + ": " + res.err.Error())
+	}
+	return res
+}
+
+// Call [f] for each suite, component(with pocket) and architecture combination
+func (t *TypeDbDebian) idxEach(f func(branch, repo, arch string)) {
+	for _, branch := range t.Branch {
+		for _, repo := range t.RepoGet() {
+			for _, arch := range t.Arch {
+				f(branch, repo, arch)
+			}
+		}
+	}
+}
+
+// Calculate(join) Packages file path base on `branch`, `repo`, `arch`
+func (t *TypeDbDebian) idxFile(branch string, repo string, arch string) string {
+	return path.Join(t.idxDir(branch, repo, arch), t.FileIndex)
+}
+
+// Return repository name of [component] in [pocket], eg. "-updates", "main" -> "updates/main"
+func debRepoJoin(pocket, component string) string {
+	if pocket == "" {
+		return component
+	}
+	return strings.TrimPrefix(pocket, "-") + "/" + component
+}
+
+// Reverse of [debRepoJoin], eg. "updates/main" -> "-updates", "main"
+func debRepoSplit(repo string) (pocket, component string) {
+	pocket, component, found := strings.Cut(repo, "/")
+	if !found {
+		return "", repo
+	}
+	return "-" + pocket, component
+}
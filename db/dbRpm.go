@@ -0,0 +1,351 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/basestruct"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+const (
+	rpmFileRepomd  = "repomd.xml"
+	rpmFilePrimary = "primary.xml"
+)
+
+// Default settings of RPM based distributions, key is distro name
+//   - `RepoPath` "{branch}" and "{arch}" are replaced
+var DbRpmDefault = map[string]*TypeDbRpmDistro{
+	"fedora": {
+		Mirrors:    []string{"https://dl.fedoraproject.org/pub/fedora/linux"},
+		Branch:     []string{"43", "44"},
+		Repository: []string{"releases", "updates"},
+		RepoPath: map[string]string{
+			"releases": "releases/{branch}/Everything/{arch}/os",
+			"updates":  "updates/{branch}/Everything/{arch}",
+		},
+		Arch: []string{"aarch64", "x86_64"},
+	},
+	"ubi": {
+		Mirrors:    []string{"https://cdn-ubi.redhat.com/content/public/ubi/dist"},
+		Branch:     []string{"9"},
+		Repository: []string{"baseos", "appstream"},
+		RepoPath: map[string]string{
+			"baseos":    "ubi{branch}/{branch}/{arch}/baseos/os",
+			"appstream": "ubi{branch}/{branch}/{arch}/appstream/os",
+		},
+		Arch: []string{"aarch64", "x86_64"},
+	},
+}
+
+// Docker platform to RPM architecture
+var RpmPlatformArch = map[string]string{
+	"linux/386":      "i686",
+	"linux/amd64":    "x86_64",
+	"linux/arm64":    "aarch64",
+	"linux/arm64/v8": "aarch64",
+	"linux/ppc64le":  "ppc64le",
+	"linux/s390x":    "s390x",
+}
+
+// Distro specific settings of RPM based distributions
+type TypeDbRpmDistro struct {
+	Mirrors    []string          // Base URL of mirrors, in order of preference
+	Branch     []string          // Releases
+	Repository []string          // Repositories
+	RepoPath   map[string]string // Repository path under mirror
+	Arch       []string          // Architectures
+}
+
+type TypeDbRpmProperty struct {
+	DirCache    *string `json:"DirCache"`    // Full path of cache directory
+	DirDbName   *string `json:"DirDbName"`   // Directory name, not full path, of database
+	Concurrency *int    `json:"Concurrency"` // Number of parallel index download
+	DistroName  *string `json:"DistroName"`  // Key of `DbRpmDefault`, eg. fedora, ubi
+
+	RpmArch    *[]string `json:"RpmArch"`    // Architecture list, Docker platform is converted, use default if empty
+	RpmBranch  *[]string `json:"RpmBranch"`  // Release list, use default if empty
+	RpmMirrors *[]string `json:"RpmMirrors"` // Base URL of mirrors, in order of preference, use default if empty
+}
+
+// RPM package database struct base on release, repository and arch, read from repodata
+//
+//   - Share database schema and queries with [TypeDbAlpine]
+//   - `Ver` is [epoch:]version-release, also stored in `Epoch`, `VerUpstream`, `Release`
+type TypeDbRpm struct {
+	*TypeDbAlpine
+	*TypeDbRpmProperty
+
+	RepoPath map[string]string
+}
+
+func (t *TypeDbRpm) New(property *TypeDbRpmProperty) *TypeDbRpm {
+	t.TypeDbRpmProperty = property
+	t.TypeDbAlpine = &TypeDbAlpine{
+		Base:     new(basestruct.Base),
+		verNewer: RpmVerNewer,
+		TypeDbAlpineProperty: &TypeDbAlpineProperty{
+			DirCache:    t.DirCache,
+			DirDbName:   t.DirDbName,
+			Concurrency: t.Concurrency,
+		},
+	}
+	t.Initialized = true
+	t.MyType = "TypeDbRpm"
+	prefix := t.MyType + ".init"
+	ezlog.Debug().N(prefix).TxtStart().Out()
+
+	t.setDefault()
+
+	t.DirDb = path.Join(*t.DirCache, *t.DirDbName, t.Distro)
+	t.FileDb = path.Join(t.DirDb, t.Distro+".db")
+
+	ezlog.Debug().N(prefix).Lm(t).Out()
+
+	ezlog.Debug().N(prefix).TxtEnd().Out()
+	return t
+}
+
+func (t *TypeDbRpm) setDefault() *TypeDbRpm {
+	prefix := t.MyType + ".setDefault"
+	t.Distro = strings.ToLower(*t.DistroName)
+	def, ok := DbRpmDefault[t.Distro]
+	if !ok {
+		t.Base.Err = errors.New(prefix + ": " + t.Distro + " not supported")
+		def = DbRpmDefault["fedora"]
+	}
+	t.FileIndex = rpmFilePrimary
+	t.Mirrors = def.Mirrors
+	t.Branch = def.Branch
+	t.Repository = def.Repository
+	t.RepoPath = def.RepoPath
+	t.Arch = def.Arch
+	t.Retry = DbAlpineDefault.Retry
+	t.Timeout = 2 * DbAlpineDefault.Timeout // primary.xml is much larger than APKINDEX
+
+	if t.RpmArch != nil && len(*t.RpmArch) > 0 {
+		t.Arch = nil
+		for _, arch := range *t.RpmArch {
+			t.Arch = append(t.Arch, t.ArchFromPlatform(arch))
+		}
+	}
+	if t.RpmBranch != nil && len(*t.RpmBranch) > 0 {
+		t.Branch = *t.RpmBranch
+	}
+	if t.RpmMirrors != nil && len(*t.RpmMirrors) > 0 {
+		t.Mirrors = *t.RpmMirrors
+	}
+	return t
+}
+
+func (t *TypeDbRpm) Connect() Idb {
+	t.TypeDbAlpine.Connect()
+	return t
+}
+
+func (t *TypeDbRpm) Dump(yes bool) Idb {
+	t.TypeDbAlpine.Dump(yes)
+	return t
+}
+
+// Update
+//   - Return immediately on error
+//   - primary.xml is only downloaded and replaced if repomd.xml changed
+func (t *TypeDbRpm) Update() Idb {
+	prefix := t.MyType + ".Update"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.stageUpdate(func() []string { return t.idxSync(t.idxEach, t.idxFetch) })
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Return RPM architecture of [platform], eg. "linux/amd64" -> "x86_64"
+//   - [platform] is returned as is if not known, Alpine x86_64 and aarch64 are the same in RPM
+func (t *TypeDbRpm) ArchFromPlatform(platform string) string {
+	if arch, ok := RpmPlatformArch[strings.ToLower(platform)]; ok {
+		return arch
+	}
+	return platform
+}
+
+// Return release of Docker [image] and [tag]
+//   - UBI release is taken from "ubiN" path element, eg. "registry.access.redhat.com/ubi8/ubi-minimal" -> "8"
+//   - Minor version and build of tag are removed, eg. "9.4-1214" -> "9"
+//   - Tag without release, eg. "latest", is the newest release in `Branch`
+func (t *TypeDbRpm) BranchFromImage(image, tag string) string {
+	for _, elem := range strings.Split(strings.ToLower(image), "/") {
+		if release, ok := strings.CutPrefix(elem, "ubi"); ok {
+			release, _, _ = strings.Cut(release, "-") // "ubi9-minimal"
+			if isDigits(release) {
+				return release
+			}
+		}
+	}
+	branch, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	branch, _, _ = strings.Cut(branch, ".")
+	if isDigits(branch) {
+		return branch
+	}
+	latest := ""
+	for _, release := range t.Branch {
+		if latest == "" || RpmVerNewer(release, latest) {
+			latest = release
+		}
+	}
+	return latest
+}
+
+// PkgVerSep return separator of package name and version in install command, eg. "dnf install pkg-ver"
+func (t *TypeDbRpm) PkgVerSep() string { return "-" }
+
+func (t *TypeDbRpm) RepoGet() []string { return t.Repository }
+
+// VerGet return newest version of [pkg] in [branch]/[repo]/[arch]
+//   - Return empty string if not found
+func (t *TypeDbRpm) VerGet(pkg string, branch, repo, arch string) (ver *string) {
+	return t.verGetNewest(pkg, branch, repo, arch, RpmVerNewer)
+}
+
+// VerNewer return true if [v1] > [v2], RPM version comparison
+func (t *TypeDbRpm) VerNewer(v1, v2 string) bool { return RpmVerNewer(v1, v2) }
+
+// Verify is not supported, repomd.xml signature is not checked
+func (t *TypeDbRpm) Verify() *[]*[]string {
+	prefix := t.MyType + ".Verify"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return &[]*[]string{}
+}
+
+// ReleaseUpdate is not supported, no release metadata is imported
+func (t *TypeDbRpm) ReleaseUpdate(file string) Idb {
+	prefix := t.MyType + ".ReleaseUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// SecdbUpdate is not supported, updateinfo is not imported
+func (t *TypeDbRpm) SecdbUpdate(files []string) Idb {
+	prefix := t.MyType + ".SecdbUpdate"
+	if t.CheckErrInit(prefix) {
+		t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+	}
+	return t
+}
+
+// Download, read and parse repodata of [index]
+//
+//   - repomd.xml is downloaded with conditional request
+//   - primary.xml(gz, xz or zst) is downloaded from the same mirror only if repomd.xml changed,
+//     its checksum is verified against repomd.xml
+//   - Run in worker, must not use ezlog, errs or database
+func (t *TypeDbRpm) idxFetch(index *TypeDbAlpineIndex) (res *idxResult) {
+	var (
+		data        []byte
+		repoPath    = t.repoPath(index.Branch, index.Repo, index.Arch)
+		filepathMd  = path.Join(t.idxDir(index.Branch, index.Repo, index.Arch), rpmFileRepomd)
+		filepathIdx = t.idxFile(index.Branch, index.Repo, index.Arch)
+		repomd      *TypeRpmRepomd
+		primary     *TypeRpmRepomdData
+	)
+	res = &idxResult{index: index}
+	res.modified, res.err = t.idxDownload(index, t.Mirrors, []string{repoPath, "repodata", rpmFileRepomd}, filepathMd)
+	if res.err == nil && res.modified {
+		data, res.err = os.ReadFile(filepathMd)
+	}
+	// Download primary only if repomd.xml changed
+	if res.err == nil && res.modified {
+		sum := sha256.Sum256(data)
+		res.hash = hex.EncodeToString(sum[:])
+		if res.hash != index.Hash {
+			repomd, res.err = rpmRepomdParse(bytes.NewReader(data))
+		}
+	}
+	if res.err == nil && repomd != nil {
+		var urlPrimary string
+		primary = repomd.DataGet("primary")
+		urlPrimary, res.err = url.JoinPath(index.Mirror, repoPath, primary.Location.Href)
+		if res.err == nil {
+			_, res.err = downloadRetry(t.client, urlPrimary, filepathIdx, "", "", t.Retry)
+		}
+		if res.err == nil {
+			data, res.err = os.ReadFile(filepathIdx)
+		}
+		if res.err == nil && primary.Checksum.Type == "sha256" {
+			sum := sha256.Sum256(data)
+			if hex.EncodeToString(sum[:]) != strings.TrimSpace(primary.Checksum.Value) {
+				res.err = errors.New(primary.Location.Href + " checksum mismatch")
+			}
+		}
+		if res.err == nil {
+			res.rows, res.err = rpmPrimaryRead(bytes.NewReader(data), index.Branch, index.Repo, index.Arch)
+			res.parsed = res.err == nil
+			res.desc = t.Distro + " " + index.Branch + " " + index.Repo + " " + repomd.Revision
+		}
+	}
+	if res.err != nil {
+		res.err = errors.New(index.Name() + ": " + res.err.Error())
+	}
+	return res
+}
+
+// Call [f] for each release, repository and architecture combination
+func (t *TypeDbRpm) idxEach(f func(branch, repo, arch string)) {
+	for _, branch := range t.Branch {
+		for _, repo := range t.Repository {
+			for _, arch := range t.Arch {
+				f(branch, repo, arch)
+			}
+		}
+	}
+}
+
+// Calculate(join) primary.xml file path base on `branch`, `repo`, `arch`
+//   - Compressed, compression is detected when read
+func (t *TypeDbRpm) idxFile(branch string, repo string, arch string) string {
+	return path.Join(t.idxDir(branch, repo, arch), t.FileIndex)
+}
+
+// Return path of [repo] under mirror, [repo] itself if not in `RepoPath`
+func (t *TypeDbRpm) repoPath(branch, repo, arch string) string {
+	p, ok := t.RepoPath[repo]
+	if !ok {
+		p = repo
+	}
+	return strings.NewReplacer("{branch}", branch, "{arch}", arch).Replace(p)
+}
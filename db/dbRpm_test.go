@@ -0,0 +1,107 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Fedora 44 fixture with file:// mirror, releases(primary.xml.gz) and updates(primary.xml.zst)
+func TestDbRpmFileMirror(t *testing.T) {
+	fixture, err := filepath.Abs("testdata/rpm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		branch      = []string{"44"}
+		arch        = []string{"linux/amd64"}
+		concurrency = 2
+		dirCache    = t.TempDir()
+		dirDb       = "db"
+		distro      = "fedora"
+		mirrors     = []string{"file://" + fixture}
+	)
+	d := new(TypeDbRpm).New(&TypeDbRpmProperty{
+		DirCache:    &dirCache,
+		DirDbName:   &dirDb,
+		Concurrency: &concurrency,
+		DistroName:  &distro,
+		RpmArch:     &arch,
+		RpmBranch:   &branch,
+		RpmMirrors:  &mirrors,
+	})
+	d.Connect().Update()
+	if d.Err() != nil {
+		t.Fatal(d.Err())
+	}
+
+	verRelease := *d.VerGet("curl", "44", "releases", "x86_64")
+	verUpdate := *d.VerGet("curl", "44", "updates", "x86_64")
+	if verRelease != "8.6.0-7.fc44" {
+		t.Errorf("releases curl = %q, want 8.6.0-7.fc44", verRelease)
+	}
+	if verUpdate != "8.6.0-10.fc44" {
+		t.Errorf("updates curl = %q, want 8.6.0-10.fc44", verUpdate)
+	}
+	if !RpmVerNewer(verUpdate, verRelease) || RpmVerNewer(verRelease, verUpdate) {
+		t.Errorf("RpmVerNewer(%q, %q) want update newer", verUpdate, verRelease)
+	}
+	// i686 record in x86_64 repository is not the x86_64 version
+	if ver := *d.VerGet("libcurl", "44", "updates", "x86_64"); ver != "8.6.0-10.fc44" {
+		t.Errorf("updates libcurl = %q, want 8.6.0-10.fc44", ver)
+	}
+	if pkg := d.PkgResolve("webclient", "44"); pkg != "curl" {
+		t.Errorf("PkgResolve(webclient) = %q, want curl", pkg)
+	}
+
+	// Unchanged repository is not replaced
+	d.Update()
+	if d.Err() != nil {
+		t.Fatal(d.Err())
+	}
+	if ver := *d.VerGet("curl", "44", "updates", "x86_64"); ver != verUpdate {
+		t.Errorf("updates curl after 2nd update = %q, want %q", ver, verUpdate)
+	}
+}
+
+func TestRpmVerNewer(t *testing.T) {
+	for _, c := range []struct {
+		v1, v2 string
+		newer  bool
+	}{
+		{"8.6.0-10.fc44", "8.6.0-7.fc44", true},
+		{"8.6.0-7.fc44", "8.6.0-10.fc44", false},
+		{"1:1.0-1", "2.0-1", true},
+		{"1.0~rc1", "1.0", false},
+		{"1.0^git1", "1.0", true},
+		{"1.0", "1.0", false},
+		{"5.5p10", "5.5p1", true},
+	} {
+		if newer := RpmVerNewer(c.v1, c.v2); newer != c.newer {
+			t.Errorf("RpmVerNewer(%q, %q) = %v, want %v", c.v1, c.v2, newer, c.newer)
+		}
+	}
+}
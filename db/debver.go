@@ -0,0 +1,135 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Return true if Debian version [v1] > [v2]
+func DebVerNewer(v1, v2 string) bool { return DebVerCmp(v1, v2) > 0 }
+
+// Compare Debian version [v1] and [v2], same as `dpkg --compare-versions`
+//   - Format: [epoch:]upstream[-revision]
+//   - Return -1, 0, 1 if [v1] is older, same, newer than [v2]
+func DebVerCmp(v1, v2 string) int {
+	e1, u1, r1 := debVerSplit(v1)
+	e2, u2, r2 := debVerSplit(v2)
+	if e1 != e2 {
+		if e1 > e2 {
+			return 1
+		}
+		return -1
+	}
+	if c := debVerRevCmp(u1, u2); c != 0 {
+		return c
+	}
+	return debVerRevCmp(r1, r2)
+}
+
+// Split Debian version [v] into epoch, upstream and revision
+//   - Revision is after the last "-", empty if none
+func debVerSplit(v string) (epoch int, upstream, revision string) {
+	v = strings.TrimSpace(v)
+	upstream = v
+	if i := strings.IndexByte(upstream, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(upstream[:i])
+		upstream = upstream[i+1:]
+	}
+	if i := strings.LastIndexByte(upstream, '-'); i >= 0 {
+		revision = upstream[i+1:]
+		upstream = upstream[:i]
+	}
+	return epoch, upstream, revision
+}
+
+// Sort weight of a non-digit character, "~" sort before anything, even end of string
+func debVerOrder(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// Compare upstream version or revision, port of dpkg verrevcmp()
+func debVerRevCmp(a, b string) int {
+	i, j := 0, 0
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	for i < len(a) || j < len(b) {
+		// non-digit part
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := 0, 0
+			if i < len(a) {
+				ac = debVerOrder(a[i])
+			}
+			if j < len(b) {
+				bc = debVerOrder(b[j])
+			}
+			if ac != bc {
+				if ac > bc {
+					return 1
+				}
+				return -1
+			}
+			i++
+			j++
+		}
+		// digit part
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		diff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if diff == 0 {
+				diff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if diff != 0 {
+			if diff > 0 {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import "testing"
+
+func TestDebVerNewer(t *testing.T) {
+	for _, c := range []struct {
+		v1, v2 string
+		newer  bool
+	}{
+		// epoch
+		{"1:1.0", "2.0", true},
+		{"2.0", "1:1.0", false},
+		{"0:1.0", "1.0", false},
+		// tilde sort before anything, even end of string
+		{"1.0~rc1", "1.0", false},
+		{"1.0", "1.0~rc1", true},
+		{"1.0~rc1", "1.0~~", true},
+		{"1.0~rc2", "1.0~rc1", true},
+		// revision
+		{"1.0-2", "1.0-10", false},
+		{"1.0-10", "1.0-2", true},
+		{"1.0-1", "1.0", true},
+		{"2.36.1-8+deb12u1", "2.36.1-8", true},
+		// letters sort before non-letters
+		{"1.0a", "1.0+", false},
+		{"1.0+", "1.0a", true},
+		{"1.0.1", "1.0a", true},
+		{"1.0a", "1.0", true},
+		// numeric
+		{"1.10", "1.9", true},
+		{"1.01", "1.1", false},
+		{"1.0", "1.0", false},
+	} {
+		if newer := DebVerNewer(c.v1, c.v2); newer != c.newer {
+			t.Errorf("DebVerNewer(%q, %q) = %v, want %v", c.v1, c.v2, newer, c.newer)
+		}
+	}
+}
+
+func TestDebVerCmp(t *testing.T) {
+	for _, c := range []struct {
+		v1, v2 string
+		cmp    int
+	}{
+		{"1:1.0", "2.0", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0-2", "1.0-10", -1},
+		{"1.0a", "1.0+", -1},
+		{"0:1.0-1", "1.0-1", 0},
+		{"1.01", "1.1", 0},
+	} {
+		if cmp := DebVerCmp(c.v1, c.v2); cmp != c.cmp {
+			t.Errorf("DebVerCmp(%q, %q) = %d, want %d", c.v1, c.v2, cmp, c.cmp)
+		}
+	}
+}
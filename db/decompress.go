@@ -0,0 +1,73 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var (
+	magicGzip = []byte{0x1f, 0x8b}
+	magicXz   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	magicZstd = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Return decompressed reader of [r]
+//   - Compression(gzip, xz or zstd) is detected from content, not file name
+//   - Caller must close the returned reader
+func decompressReader(r io.Reader) (rc io.ReadCloser, err error) {
+	var (
+		br    = bufio.NewReader(r)
+		magic []byte
+	)
+	magic, err = br.Peek(len(magicXz))
+	if err == nil || err == io.EOF {
+		err = nil
+		switch {
+		case bytes.HasPrefix(magic, magicGzip):
+			rc, err = gzip.NewReader(br)
+		case bytes.HasPrefix(magic, magicXz):
+			var xr *xz.Reader
+			xr, err = xz.NewReader(br)
+			rc = io.NopCloser(xr)
+		case bytes.HasPrefix(magic, magicZstd):
+			var zr *zstd.Decoder
+			zr, err = zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+			if err == nil {
+				rc = zr.IOReadCloser()
+			}
+		default:
+			err = errors.New("unknown compression")
+		}
+	}
+	return rc, err
+}
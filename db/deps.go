@@ -0,0 +1,193 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Deps return runtime dependencies(D:) of [pkg] in [branch]/[arch]
+//   - [repo] limit where [pkg] is looked up, empty for all repositories
+//   - Dependencies are resolved to providing packages within [branch]
+//   - [recursive] walk the whole dependency tree, each package is listed once
+//   - Return rows: dependency(indented by depth), pkg, ver, repo
+func (t *TypeDbAlpine) Deps(pkg, branch, repo, arch string, recursive bool) *[]*[]string {
+	prefix := t.MyType + ".Deps"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var record *TypeDbAlpineRecord
+		if t.Base.Err == nil {
+			record, t.Base.Err = recordGet(t.Db, pkg, branch, repo, arch, t.verNewer)
+		}
+		if t.Base.Err == nil && record == nil {
+			t.Base.Err = errors.New(branch + "/" + repo + "/" + arch + ": " + pkg + " not found")
+		}
+		if t.Base.Err == nil {
+			visited := map[string]bool{record.Pkg: true}
+			var walk func(r *TypeDbAlpineRecord, depth int)
+			walk = func(r *TypeDbAlpineRecord, depth int) {
+				for _, dep := range r.Depends {
+					if t.Base.Err != nil || dep.Conflict {
+						continue
+					}
+					var provider *TypeDbAlpineRecord
+					provider, t.Base.Err = providerGet(t.Db, dep.Name, branch, arch, t.verNewer)
+					strArr := []string{strings.Repeat("  ", depth) + dep.String(), "<not found>", "", ""}
+					if provider != nil {
+						strArr = []string{strArr[0], provider.Pkg, provider.Ver, provider.Repo}
+					}
+					strArrArr = append(strArrArr, &strArr)
+					if recursive && provider != nil && !visited[provider.Pkg] {
+						visited[provider.Pkg] = true
+						walk(provider, depth+1)
+					}
+				}
+			}
+			walk(record, 0)
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
+
+// Rdeps return packages depending on [pkg] in [branch]/[arch]
+//   - Dependency on anything [pkg] provides(p:) is included
+//   - [repo] limit where dependents are looked up, empty for all repositories
+//   - Return rows: pkg, ver, repo, dependency
+func (t *TypeDbAlpine) Rdeps(pkg, branch, repo, arch string) *[]*[]string {
+	prefix := t.MyType + ".Rdeps"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var record *TypeDbAlpineRecord
+		if t.Base.Err == nil {
+			record, t.Base.Err = recordGet(t.Db, pkg, branch, "", arch, t.verNewer)
+		}
+		if t.Base.Err == nil && record == nil {
+			t.Base.Err = errors.New(branch + "/" + arch + ": " + pkg + " not found")
+		}
+		type row struct {
+			Dep string
+			TypeDbAlpineRecord
+		}
+		var rows []row
+		if t.Base.Err == nil {
+			names := []string{record.Pkg}
+			for _, p := range record.Provides {
+				names = append(names, p.Name)
+			}
+			result := t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Select("type_db_alpine_depends.name AS dep, type_db_alpine_records.pkg, type_db_alpine_records.ver, type_db_alpine_records.repo").
+				Joins("JOIN type_db_alpine_depends ON type_db_alpine_depends.record_id = type_db_alpine_records.id").
+				Where("type_db_alpine_records.branch = ? AND type_db_alpine_records.arch = ?", branch, arch).
+				Where("type_db_alpine_depends.name IN ? AND NOT type_db_alpine_depends.conflict", names)
+			if repo != "" {
+				result = result.Where("type_db_alpine_records.repo = ?", repo)
+			}
+			t.Base.Err = result.Order("type_db_alpine_records.pkg").Scan(&rows).Error
+		}
+		if t.Base.Err == nil {
+			for _, r := range rows {
+				strArr := []string{r.Pkg, r.Ver, r.Repo, r.Dep}
+				strArrArr = append(strArrArr, &strArr)
+			}
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
+
+// Return record of [pkg] in [branch]/[repo]/[arch] with child tables loaded
+//   - [repo] empty for all repositories
+//   - Newest version by [verNewer] if index has more than one, eg. Wolfi
+//   - Return nil if not found
+func recordGet(db *gorm.DB, pkg, branch, repo, arch string, verNewer func(v1, v2 string) bool) (record *TypeDbAlpineRecord, err error) {
+	var rows []TypeDbAlpineRecord
+	where := map[string]interface{}{
+		"Branch": branch,
+		"Arch":   arch,
+		"Pkg":    pkg,
+	}
+	if repo != "" {
+		where["Repo"] = repo
+	}
+	err = db.
+		Select("id", "ver").
+		Where(where).
+		Order("id").
+		Find(&rows).Error
+	if err == nil && len(rows) > 0 {
+		newest := rows[0]
+		for _, row := range rows[1:] {
+			if verNewer(row.Ver, newest.Ver) {
+				newest = row
+			}
+		}
+		record = new(TypeDbAlpineRecord)
+		err = db.
+			Preload("Depends").
+			Preload("Provides").
+			First(record, newest.ID).Error
+	}
+	return record, err
+}
+
+// Return record of package providing [name] in [branch]/[arch]
+//   - [name] can be a package name or anything in provides(p:)
+//   - Highest provider priority(k:) wins
+//   - Return nil if not found
+func providerGet(db *gorm.DB, name, branch, arch string, verNewer func(v1, v2 string) bool) (record *TypeDbAlpineRecord, err error) {
+	record, err = recordGet(db, name, branch, "", arch, verNewer)
+	if err == nil && record == nil {
+		var rows []TypeDbAlpineRecord
+		err = db.
+			Select("type_db_alpine_records.*").
+			Joins("JOIN type_db_alpine_provides ON type_db_alpine_provides.record_id = type_db_alpine_records.id").
+			Where("type_db_alpine_records.branch = ? AND type_db_alpine_records.arch = ? AND type_db_alpine_provides.name = ?", branch, arch, name).
+			Order("type_db_alpine_records.provider_priority DESC, type_db_alpine_records.pkg").
+			Limit(1).
+			Find(&rows).Error
+		if err == nil && len(rows) > 0 {
+			record, err = recordGet(db, rows[0].Pkg, branch, rows[0].Repo, arch, verNewer)
+		}
+	}
+	return record, err
+}
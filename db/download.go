@@ -0,0 +1,118 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Base delay between retries, doubled on each retry
+var downloadBackoff = time.Second
+
+// Result of [download]
+type downloadResult struct {
+	Modified     bool   // false if server return 304 Not Modified
+	ETag         string // ETag of response
+	LastModified string // Last-Modified of response
+	StatusCode   int
+}
+
+// Return HTTP client with [timeout], file:// URL is read from local file system
+//   - file:// support Last-Modified and If-Modified-Since as http(s)
+func downloadClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// [download] with retry and exponential backoff
+//   - Client error(4xx) is not retried
+//   - Run in worker, must not use ezlog or errs
+func downloadRetry(client *http.Client, url, filepath, etag, lastModified string, retry int) (res *downloadResult, err error) {
+	delay := downloadBackoff
+	for attempt := 0; attempt <= retry; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		res, err = download(client, url, filepath, etag, lastModified)
+		if err == nil || (res.StatusCode >= 400 && res.StatusCode < 500) {
+			break
+		}
+	}
+	return res, err
+}
+
+// URL download to file
+//   - Conditional request if [etag] or [lastModified] is not empty
+//   - [filepath] is untouched if server return 304 Not Modified
+//   - Run in worker, must not use ezlog or errs
+func download(client *http.Client, url, filepath, etag, lastModified string) (res *downloadResult, err error) {
+	var (
+		out  *os.File
+		req  *http.Request
+		resp *http.Response
+	)
+	res = new(downloadResult)
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err = http.NewRequest(http.MethodGet, url, nil)
+	if err == nil {
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+		resp, err = client.Do(req)
+	}
+	if err == nil {
+		defer resp.Body.Close()
+		res.StatusCode = resp.StatusCode
+		if resp.StatusCode >= 400 { // eg. 404
+			err = errors.New(url + " " + resp.Status)
+		}
+	}
+	if err == nil && resp.StatusCode != http.StatusNotModified {
+		res.Modified = true
+		res.ETag = resp.Header.Get("ETag")
+		res.LastModified = resp.Header.Get("Last-Modified")
+		// Write to tmp file first, cached file stay intact on error
+		out, err = os.Create(filepath + ".tmp")
+		if err == nil {
+			_, err = io.Copy(out, resp.Body)
+			out.Close()
+		}
+		if err == nil {
+			err = os.Rename(filepath+".tmp", filepath)
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,183 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Export formats
+var ExportFormats = []string{"json", "csv", "ndjson"}
+
+// Number of records written between csv flushes
+const exportBatch = 1000
+
+// Export package records as [format] to [w]
+//
+//   - [format]: json(array), csv(with header), ndjson(one object per line)
+//   - [branch], [repo], [arch] filter records, empty for all
+//   - Records are streamed with a database cursor, ordered by branch, repo, arch, pkg
+//   - Columns are the scalar fields of [TypeDbAlpineRecord]
+//   - Return number of records exported
+func (t *TypeDbAlpine) Export(w io.Writer, format, branch, repo, arch string) (count int) {
+	prefix := t.MyType + ".Export"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		var (
+			columns = exportColumns()
+			csvW    *csv.Writer
+			tx      *gorm.DB
+		)
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		if t.Base.Err == nil {
+			switch format {
+			case "json":
+				_, t.Base.Err = io.WriteString(w, "[")
+			case "csv":
+				csvW = csv.NewWriter(w)
+				header := make([]string, len(columns))
+				for i, c := range columns {
+					header[i] = c.name
+				}
+				t.Base.Err = csvW.Write(header)
+			case "ndjson":
+			default:
+				t.Base.Err = errors.New("unknown format " + format + ", supported: " + strings.Join(ExportFormats, ", "))
+			}
+		}
+		if t.Base.Err == nil {
+			tx = t.Db.Model(&TypeDbAlpineRecord{})
+			for column, value := range map[string]string{"branch": branch, "repo": repo, "arch": arch} {
+				if value != "" {
+					tx = tx.Where(column+" = ?", value)
+				}
+			}
+			// Stream with a cursor, FindInBatches page by id and would break the order
+			var rows *sql.Rows
+			rows, t.Base.Err = tx.Order("branch, repo, arch, pkg, id").Rows()
+			if t.Base.Err == nil {
+				for rows.Next() && t.Base.Err == nil {
+					var record TypeDbAlpineRecord
+					t.Base.Err = t.Db.ScanRows(rows, &record)
+					if t.Base.Err == nil {
+						switch format {
+						case "json":
+							before := ",\n"
+							if count == 0 {
+								before = "\n"
+							}
+							t.Base.Err = exportJson(w, &record, before, "")
+						case "csv":
+							t.Base.Err = csvW.Write(exportRow(&record, columns))
+						case "ndjson":
+							t.Base.Err = exportJson(w, &record, "", "\n")
+						}
+					}
+					if t.Base.Err == nil {
+						count++
+					}
+					if csvW != nil && t.Base.Err == nil && count%exportBatch == 0 {
+						csvW.Flush()
+						t.Base.Err = csvW.Error()
+					}
+				}
+				if t.Base.Err == nil {
+					t.Base.Err = rows.Err()
+				}
+				rows.Close()
+			}
+		}
+		if t.Base.Err == nil {
+			switch format {
+			case "json":
+				_, t.Base.Err = io.WriteString(w, "\n]\n")
+			case "csv":
+				csvW.Flush()
+				t.Base.Err = csvW.Error()
+			}
+		}
+		ezlog.Debug().N(prefix).N("Rows").M(count).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return count
+}
+
+// Export column, a scalar field of [TypeDbAlpineRecord]
+type exportColumn struct {
+	name  string // json name
+	index int    // field index
+}
+
+// Return export columns, scalar fields of [TypeDbAlpineRecord] in declaration order
+//   - Column name is json name, fields with json "-" and slices(Depends, Provides, InstallIf) are skipped
+func exportColumns() (columns []exportColumn) {
+	rt := reflect.TypeFor[TypeDbAlpineRecord]()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		switch field.Type.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Struct, reflect.Pointer:
+			continue
+		}
+		if name != "-" {
+			columns = append(columns, exportColumn{name: name, index: i})
+		}
+	}
+	return columns
+}
+
+// Return [columns] of [record] as strings
+func exportRow(record *TypeDbAlpineRecord, columns []exportColumn) (row []string) {
+	rv := reflect.ValueOf(record).Elem()
+	for _, c := range columns {
+		row = append(row, fmt.Sprint(rv.Field(c.index).Interface()))
+	}
+	return row
+}
+
+// Write [record] as json object to [w], between [before] and [after]
+func exportJson(w io.Writer, record *TypeDbAlpineRecord, before, after string) (err error) {
+	var data []byte
+	data, err = json.Marshal(record)
+	if err == nil {
+		_, err = io.WriteString(w, before+string(data)+after)
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Full-text index table of package name, description and URL, rowid is record ID
+const ftsTable = "type_db_alpine_texts"
+
+// Table marking the full-text index stale, it exists if records were changed by a build without FTS5
+const ftsStaleTable = "type_db_alpine_texts_stale"
+
+// Column weights of full-text ranking: pkg, desc, url
+var ftsWeight = []float64{10, 2, 1}
+
+// Error of full-text search and index update if SQLite is built without FTS5
+var errFts5 = errors.New("full-text index needs SQLite FTS5, build with: go build -tags sqlite_fts5")
+
+// Create FTS5 full-text index table if not exist, and fill it from all records
+//   - Need SQLite FTS5(go build -tags sqlite_fts5), table is not created without it, see SearchText()
+//   - Table of earlier versions which is not FTS5, eg. FTS4, is replaced
+//   - Stale table, see [ftsStale], is refilled from all records
+//   - Called on every connect, table is created once a build with FTS5 open the database
+func ftsCreate(db *gorm.DB) (err error) {
+	var sql string
+	err = db.Raw("SELECT sql FROM sqlite_master WHERE name = ?", ftsTable).Scan(&sql).Error
+	if err != nil || (ftsIs5(sql) && !ftsIsStale(db)) {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) (err error) {
+		if ftsIs5(sql) {
+			err = tx.Exec("DELETE FROM " + ftsTable).Error
+		} else {
+			if sql != "" {
+				err = tx.Exec("DROP TABLE " + ftsTable).Error
+			}
+			if err == nil {
+				err = tx.Exec("CREATE VIRTUAL TABLE " + ftsTable + " USING fts5(pkg, desc, url)").Error
+			}
+		}
+		if err != nil && strings.Contains(err.Error(), "no such module") {
+			return nil
+		}
+		if err == nil {
+			err = tx.Exec("INSERT INTO " + ftsTable + " (rowid, pkg, desc, url) SELECT id, pkg, desc, url FROM type_db_alpine_records").Error
+		}
+		if err == nil {
+			err = tx.Exec("DROP TABLE IF EXISTS " + ftsStaleTable).Error
+		}
+		return err
+	})
+}
+
+// Return true if full-text index table [sql] is FTS5
+func ftsIs5(sql string) bool {
+	return strings.Contains(strings.ToLower(sql), "fts5")
+}
+
+// Return true if full-text index table exist, it may not be usable if SQLite is built without FTS5
+func ftsExist(db *gorm.DB) bool {
+	var sql string
+	db.Raw("SELECT sql FROM sqlite_master WHERE name = ?", ftsTable).Scan(&sql)
+	return ftsIs5(sql)
+}
+
+// Return true if full-text index is marked stale, see [ftsStale]
+func ftsIsStale(db *gorm.DB) bool {
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE name = ?", ftsStaleTable).Scan(&count)
+	return count > 0
+}
+
+// Mark full-text index stale if [err] is missing FTS5, it is refilled by [ftsCreate] of a build with FTS5
+//   - Records can be updated by a build without FTS5, full-text index is not updated
+func ftsStale(tx *gorm.DB, err error) error {
+	if ftsErr(err) == errFts5 {
+		err = tx.Exec("CREATE TABLE IF NOT EXISTS " + ftsStaleTable + " (id INTEGER)").Error
+	}
+	return err
+}
+
+// Remove full-text index of records with ID in [ids], if full-text index table exist
+func ftsDelete(tx *gorm.DB, ids *gorm.DB) error {
+	if !ftsExist(tx) {
+		return nil
+	}
+	return ftsStale(tx, tx.Exec("DELETE FROM "+ftsTable+" WHERE rowid IN (?)", ids).Error)
+}
+
+// Add full-text index of records with ID in [ids], if full-text index table exist
+func ftsInsert(tx *gorm.DB, ids *gorm.DB) error {
+	if !ftsExist(tx) {
+		return nil
+	}
+	return ftsStale(tx, tx.Exec("INSERT INTO "+ftsTable+" (rowid, pkg, desc, url) SELECT id, pkg, desc, url FROM type_db_alpine_records WHERE id IN (?)", ids).Error)
+}
+
+// Return `errFts5` if [err] is missing FTS5, eg. database of a build with FTS5 opened by one without
+func ftsErr(err error) error {
+	if err != nil && strings.Contains(err.Error(), "no such module") {
+		return errFts5
+	}
+	return err
+}
+
+// Return full-text query of [text], each word is a quoted phrase, all must match
+//   - eg. `http proxy` -> `"http" "proxy"`, operators are not supported
+func ftsQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// SearchText search packages by words in name, description and URL
+//   - [option] filters and newest version only, match mode is not used
+//   - Ranked by relevance, name match rank higher than description, then URL
+//   - Error if SQLite is built without FTS5
+//   - Return rows of `SearchColumns`
+func (t *TypeDbAlpine) SearchText(text string, option *TypeDbSearchOption) *[]*[]string {
+	prefix := t.MyType + ".SearchText"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		var (
+			query = ftsQuery(text)
+			rows  []TypeDbAlpineRecord
+		)
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		if t.Base.Err == nil && query == "" {
+			t.Base.Err = errors.New("empty search text")
+		}
+		if t.Base.Err == nil && !ftsExist(t.Db) {
+			t.Base.Err = errFts5
+		}
+		if t.Base.Err == nil {
+			result := t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Select("type_db_alpine_records.pkg, type_db_alpine_records.ver, type_db_alpine_records.branch, type_db_alpine_records.repo, type_db_alpine_records.arch, type_db_alpine_records.desc, type_db_alpine_records.url").
+				Joins("JOIN "+ftsTable+" ON "+ftsTable+".rowid = type_db_alpine_records.id").
+				Where(ftsTable+" MATCH ?", query)
+			result = option.filter(result, "type_db_alpine_records")
+			result = result.Order(gorm.Expr("bm25("+ftsTable+", ?, ?, ?)", ftsWeight[0], ftsWeight[1], ftsWeight[2]))
+			t.Base.Err = ftsErr(result.
+				Order("type_db_alpine_records.branch, type_db_alpine_records.repo, type_db_alpine_records.arch, type_db_alpine_records.pkg").
+				Scan(&rows).Error)
+		}
+		if t.Base.Err == nil {
+			if option.Latest {
+				rows = t.searchLatest(rows)
+			}
+			for _, r := range rows {
+				strArr := []string{r.Pkg, r.Ver, r.Repo, r.Branch, r.Arch, r.Desc}
+				strArrArr = append(strArrArr, &strArr)
+			}
+		}
+		ezlog.Debug().N(prefix).N(text).M(len(strArrArr)).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
@@ -0,0 +1,87 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Version history of a package, updated on each database refresh
+type TypeDbAlpineHistory struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	Pkg       string    `json:"Pkg" gorm:"uniqueIndex:idx_alpine_history"`
+	Branch    string    `json:"Branch" gorm:"uniqueIndex:idx_alpine_history"`
+	Repo      string    `json:"Repo" gorm:"uniqueIndex:idx_alpine_history"`
+	Arch      string    `json:"Arch" gorm:"uniqueIndex:idx_alpine_history"`
+	Ver       string    `json:"Ver" gorm:"uniqueIndex:idx_alpine_history"`
+	FirstSeen time.Time `json:"FirstSeen"`
+	LastSeen  time.Time `json:"LastSeen"`
+}
+
+// History return version history of [pkg]
+//   - Return one row per version: pkg, ver, branch, repo, arch, first seen, last seen
+func (t *TypeDbAlpine) History(pkg string) *[]*[]string {
+	prefix := t.MyType + ".History"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var rows []TypeDbAlpineHistory
+		if t.Base.Err == nil {
+			result := t.Db.
+				Where(map[string]interface{}{"Pkg": pkg}).
+				Order("branch, repo, arch, first_seen").
+				Find(&rows)
+			t.Base.Err = result.Error
+		}
+		if t.Base.Err == nil {
+			for _, r := range rows {
+				strArr := []string{r.Pkg, r.Ver, r.Branch, r.Repo, r.Arch, r.FirstSeen.Format(time.DateTime), r.LastSeen.Format(time.DateTime)}
+				strArrArr = append(strArrArr, &strArr)
+			}
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
+
+// Record versions currently in [index] into history
+//   - New version get `FirstSeen` and `LastSeen` = [now]
+//   - Existing version get `LastSeen` = [now]
+func histUpdate(db *gorm.DB, index *TypeDbAlpineIndex, now time.Time) error {
+	return db.Exec(`INSERT INTO type_db_alpine_histories (pkg, branch, repo, arch, ver, first_seen, last_seen)
+		SELECT pkg, branch, repo, arch, ver, ?, ? FROM type_db_alpine_records
+		WHERE branch = ? AND repo = ? AND arch = ?
+		ON CONFLICT (pkg, branch, repo, arch, ver) DO UPDATE SET last_seen = excluded.last_seen`,
+		now, now, index.Branch, index.Repo, index.Arch).Error
+}
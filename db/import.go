@@ -0,0 +1,259 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+// Import indexes from local mirror copy [src], directory or tar(.gz/.xz/.zst) archive
+//
+//   - [src] has the same layout as mirror, eg. <branch>/<repo>/<arch>/APKINDEX.tar.gz
+//   - Only indexes found in [src] are imported, others are untouched
+//   - Same staging and import path as [TypeDbAlpine.Update]
+func (t *TypeDbAlpine) Import(src string) Idb {
+	prefix := t.MyType + ".Import"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.importMirror(src, func(dir string) {
+			each, err := t.importEach(dir)
+			if err == nil {
+				t.stageUpdate(func() []string { return t.idxUpdate(each, t.idxFetch) })
+			} else {
+				t.Base.Err = errs.New(prefix, err.Error())
+			}
+		})
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Use local mirror copy [src] as the only mirror while running [update]
+//   - Archive is extracted into a temporary directory, removed afterward
+//   - [update] get the directory of the mirror copy
+func (t *TypeDbAlpine) importMirror(src string, update func(dir string)) {
+	prefix := t.MyType + ".importMirror"
+	var (
+		dir     string
+		err     error
+		info    os.FileInfo
+		mirrors = t.Mirrors
+	)
+	info, err = os.Stat(src)
+	if err == nil {
+		if info.IsDir() {
+			dir = src
+		} else {
+			dir, err = os.MkdirTemp("", "go-auto-docker-import-")
+			if err == nil {
+				defer os.RemoveAll(dir)
+				err = tarExtract(src, dir)
+			}
+		}
+	}
+	if err == nil {
+		dir, err = filepath.Abs(dir)
+	}
+	if err == nil {
+		ezlog.Debug().N(prefix).M(dir).Out()
+		t.Mirrors = []string{fileURL(dir)}
+		update(dir)
+		t.Mirrors = mirrors
+	}
+	if err != nil {
+		t.Base.Err = errs.New(prefix, err.Error())
+	}
+}
+
+// Return iterator of indexes found in [dir]
+//   - Index path is matched against `IndexPath`, eg. "{branch}/{repo}/{arch}"
+//   - `BranchRolling` is used if `IndexPath` has no branch
+//   - Error if no index found
+func (t *TypeDbAlpine) importEach(dir string) (each func(f func(branch, repo, arch string)), err error) {
+	var (
+		found    [][3]string
+		template = strings.Split(t.IndexPath, "/")
+		fileIdx  = t.FileIndex + extTgz
+	)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != fileIdx {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != len(template) {
+			return nil
+		}
+		idx := [3]string{t.BranchRolling}
+		for i, part := range template {
+			switch part {
+			case "{branch}":
+				idx[0] = parts[i]
+			case "{repo}":
+				idx[1] = parts[i]
+			case "{arch}":
+				idx[2] = parts[i]
+			default:
+				if part != parts[i] {
+					return nil
+				}
+			}
+		}
+		found = append(found, idx)
+		return nil
+	})
+	if err == nil && len(found) == 0 {
+		err = errors.New("no " + fileIdx + " in " + dir + ", expected layout: " + t.IndexPath + "/" + fileIdx)
+	}
+	each = func(f func(branch, repo, arch string)) {
+		for _, idx := range found {
+			f(idx[0], idx[1], idx[2])
+		}
+	}
+	return each, err
+}
+
+// Import indexes from local mirror copy [src], directory or tar(.gz/.xz/.zst) archive
+//   - [src] has the same layout as mirror, eg. dists/<suite>/<component>/binary-<arch>/Packages.xz
+//   - All configured indexes must be in [src]
+func (t *TypeDbDebian) Import(src string) Idb {
+	prefix := t.MyType + ".Import"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		ports := t.MirrorsPorts
+		t.MirrorsPorts = nil
+		t.importMirror(src, func(string) { t.Update() })
+		t.MirrorsPorts = ports
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Import indexes from local mirror copy [src], directory or tar(.gz/.xz/.zst) archive
+//   - [src] has the same layout as mirror, eg. <release>/Everything/<arch>/os/repodata/repomd.xml
+//   - All configured indexes must be in [src]
+func (t *TypeDbRpm) Import(src string) Idb {
+	prefix := t.MyType + ".Import"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.importMirror(src, func(string) { t.Update() })
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Import indexes from local mirror copy [src], directory or tar(.gz/.xz/.zst) archive
+//   - [src] has the same layout as mirror, eg. <repo>/os/<arch>/<repo>.db
+//   - All configured indexes must be in [src]
+func (t *TypeDbArchLinux) Import(src string) Idb {
+	prefix := t.MyType + ".Import"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		t.importMirror(src, func(string) { t.Update() })
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Return file:// URL of absolute path [dir]
+func fileURL(dir string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()
+}
+
+// Extract tar archive [src] into [dir]
+//   - Archive can be plain or compressed(gzip, xz or zstd)
+//   - Only regular files and directories are extracted, entries outside [dir] are rejected
+func tarExtract(src, dir string) (err error) {
+	var (
+		f  *os.File
+		r  io.Reader
+		rc io.ReadCloser
+	)
+	f, err = os.Open(src)
+	if err == nil {
+		defer f.Close()
+		rc, err = decompressReader(f)
+		if err == nil {
+			defer rc.Close()
+			r = rc
+		} else {
+			// Plain tar
+			_, err = f.Seek(0, io.SeekStart)
+			r = f
+		}
+	}
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		var hdr *tar.Header
+		hdr, err = tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(strings.TrimPrefix(hdr.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return errors.New(src + ": invalid path " + hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, os.ModePerm)
+		case tar.TypeReg:
+			var out *os.File
+			err = os.MkdirAll(filepath.Dir(target), os.ModePerm)
+			if err == nil {
+				out, err = os.Create(target)
+			}
+			if err == nil {
+				_, err = io.Copy(out, tr)
+				out.Close()
+			}
+			if err == nil {
+				err = os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
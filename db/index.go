@@ -0,0 +1,124 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// State of one APKINDEX(branch/repo/arch) in database
+//
+//   - `ETag` and `LastModified` are used for conditional download
+//   - `Hash` is sha256 of APKINDEX.tar.gz, rows are only replaced when it changed
+type TypeDbAlpineIndex struct {
+	ID           uint      `json:"-" gorm:"primaryKey"`
+	Branch       string    `json:"Branch" gorm:"uniqueIndex:idx_alpine_index"`
+	Repo         string    `json:"Repo" gorm:"uniqueIndex:idx_alpine_index"`
+	Arch         string    `json:"Arch" gorm:"uniqueIndex:idx_alpine_index"`
+	ETag         string    `json:"ETag,omitempty"`
+	LastModified string    `json:"LastModified,omitempty"`
+	Mirror       string    `json:"Mirror,omitempty"` // Mirror base URL which served the index
+	Hash         string    `json:"Hash,omitempty"`
+	Description  string    `json:"Description,omitempty"` // DESCRIPTION in APKINDEX.tar.gz
+	SignName     string    `json:"SignName,omitempty"`    // .SIGN.RSA.<key name> in APKINDEX.tar.gz
+	Rows         int       `json:"Rows"`
+	UpdatedAt    time.Time `json:"UpdatedAt"` // last time rows were replaced
+	FetchedAt    time.Time `json:"FetchedAt"` // last time index was fetched, changed or not
+}
+
+func (t *TypeDbAlpineIndex) Name() string { return t.Branch + "/" + t.Repo + "/" + t.Arch }
+
+// Return index state of [branch]/[repo]/[arch], a new one if not in database
+func idxGet(db *gorm.DB, branch, repo, arch string) (index *TypeDbAlpineIndex, err error) {
+	index = &TypeDbAlpineIndex{
+		Branch: branch,
+		Repo:   repo,
+		Arch:   arch,
+	}
+	result := db.
+		Where(map[string]interface{}{
+			"Branch": branch,
+			"Repo":   repo,
+			"Arch":   arch,
+		}).
+		Limit(1).
+		Find(index)
+	return index, result.Error
+}
+
+// Delete records of [index] with their child rows and full-text index, [tx] is a transaction
+func idxRowsDelete(tx *gorm.DB, index *TypeDbAlpineIndex) (err error) {
+	ids := tx.Model(&TypeDbAlpineRecord{}).Select("id").Where(idxWhere(index))
+	err = ftsDelete(tx, ids)
+	for _, child := range []any{&TypeDbAlpineDepend{}, &TypeDbAlpineProvide{}, &TypeDbAlpineInstallIf{}} {
+		if err == nil {
+			err = tx.Where("record_id IN (?)", ids).Delete(child).Error
+		}
+	}
+	if err == nil {
+		err = tx.Where(idxWhere(index)).Delete(&TypeDbAlpineRecord{}).Error
+	}
+	return err
+}
+
+// Return condition of records of [index]
+func idxWhere(index *TypeDbAlpineIndex) map[string]interface{} {
+	return map[string]interface{}{
+		"Branch": index.Branch,
+		"Repo":   index.Repo,
+		"Arch":   index.Arch,
+	}
+}
+
+// Delete [index] with its records, child rows and full-text index in one transaction
+func idxDelete(db *gorm.DB, index *TypeDbAlpineIndex) error {
+	return db.Transaction(func(tx *gorm.DB) (err error) {
+		err = idxRowsDelete(tx, index)
+		if err == nil {
+			err = tx.Delete(index).Error
+		}
+		return err
+	})
+}
+
+// Replace all rows of [index] with [rows] in one transaction
+func idxReplace(db *gorm.DB, index *TypeDbAlpineIndex, rows []TypeDbAlpineRecord) error {
+	return db.Transaction(func(tx *gorm.DB) (err error) {
+		err = idxRowsDelete(tx, index)
+		if err == nil && len(rows) > 0 {
+			err = tx.CreateInBatches(rows, 1000).Error
+		}
+		if err == nil && len(rows) > 0 {
+			err = ftsInsert(tx, tx.Model(&TypeDbAlpineRecord{}).Select("id").Where(idxWhere(index)))
+		}
+		if err == nil {
+			index.Rows = len(rows)
+			err = tx.Save(index).Error
+		}
+		return err
+	})
+}
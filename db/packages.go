@@ -0,0 +1,157 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Split Debian dependency field into [TypeDbAlpineDep]
+//
+//   - "libc6 (>= 2.36), libssl3 | libssl1.1" -> libc6>=2.36, libssl3
+//   - Only the first of alternatives("|") is kept
+//   - Architecture qualifier is removed, eg. "python3:any" -> python3
+func debDepSplit(s string, conflict bool) (deps []TypeDbAlpineDep) {
+	for _, d := range strings.Split(s, ",") {
+		d = strings.TrimSpace(strings.Split(d, "|")[0])
+		if d == "" {
+			continue
+		}
+		dep := TypeDbAlpineDep{Conflict: conflict}
+		name, ver, found := strings.Cut(d, "(")
+		dep.Name = strings.TrimSpace(name)
+		if i := strings.IndexByte(dep.Name, ':'); i >= 0 {
+			dep.Name = dep.Name[:i]
+		}
+		if found {
+			ver = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(ver), ")"))
+			i := strings.IndexFunc(ver, func(r rune) bool { return !strings.ContainsRune("<>=", r) })
+			if i > 0 {
+				dep.Op = ver[:i]
+				dep.Ver = strings.TrimSpace(ver[i:])
+			}
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// Parse Debian Packages content from [r]
+//
+//   - Records are separated by empty line
+//   - Each line is in "<Field>: <value>", continuation lines are ignored
+//   - `Origin` is the source package, same as `Pkg` if "Source:" is absent
+//   - "Conflicts:" and "Breaks:" are stored as conflict in `Depends`
+func debPackagesParse(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var (
+		record  *TypeDbAlpineRecord
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	flush := func() {
+		if record != nil && record.Pkg != "" {
+			if record.Origin == "" {
+				record.Origin = record.Pkg
+			}
+			rows = append(rows, *record)
+		}
+		record = nil
+	}
+	for scanner.Scan() {
+		l := scanner.Text()
+		if len(l) == 0 {
+			flush()
+			continue
+		}
+		if l[0] == ' ' || l[0] == '\t' {
+			continue
+		}
+		field, v, found := strings.Cut(l, ":")
+		if !found {
+			continue
+		}
+		if record == nil {
+			record = &TypeDbAlpineRecord{
+				Branch: branch,
+				Repo:   repo,
+				Arch:   arch,
+			}
+		}
+		v = strings.TrimSpace(v)
+		switch field {
+		case "Package":
+			record.Pkg = v
+		case "Version":
+			record.Ver = v
+		case "Architecture":
+			record.PkgArch = v
+		case "Source":
+			record.Origin, _, _ = strings.Cut(v, " ")
+		case "Maintainer":
+			record.Maintainer = v
+		case "Installed-Size":
+			record.InstalledSize, _ = strconv.ParseInt(v, 10, 64)
+			record.InstalledSize *= 1024
+		case "Size":
+			record.Size, _ = strconv.ParseInt(v, 10, 64)
+		case "SHA256":
+			record.Checksum = v
+		case "Description":
+			record.Desc = v
+		case "Homepage":
+			record.Url = v
+		case "Depends", "Pre-Depends":
+			for _, dep := range debDepSplit(v, false) {
+				record.Depends = append(record.Depends, TypeDbAlpineDepend{dep})
+			}
+		case "Conflicts", "Breaks":
+			for _, dep := range debDepSplit(v, true) {
+				record.Depends = append(record.Depends, TypeDbAlpineDepend{dep})
+			}
+		case "Provides":
+			for _, dep := range debDepSplit(v, false) {
+				record.Provides = append(record.Provides, TypeDbAlpineProvide{dep})
+			}
+		}
+	}
+	flush()
+	err = scanner.Err()
+	return rows, err
+}
+
+// Read compressed Debian Packages from [r]
+//   - Compression(gzip or xz) is detected from content, not file name
+func debPackagesRead(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var rc io.ReadCloser
+	rc, err = decompressReader(r)
+	if err == nil {
+		defer rc.Close()
+		rows, err = debPackagesParse(rc, branch, repo, arch)
+	}
+	return rows, err
+}
@@ -0,0 +1,154 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+// Prefixes tried when resolving a virtual package name
+var AlpineProvidePrefix = []string{"", "cmd:", "so:", "pc:"}
+
+// PkgResolve return the real package name of [name] in [branch]
+//   - [name] is returned as is if it is a package
+//   - Otherwise the package providing [name] (p:), also try with "cmd:", "so:", "pc:" prefix
+//   - Highest provider priority(k:) wins
+//   - Return empty string if not found
+func (t *TypeDbAlpine) PkgResolve(name, branch string) (pkg string) {
+	prefix := t.MyType + ".PkgResolve"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var count int64
+		if t.Base.Err == nil {
+			t.Base.Err = t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Where(map[string]interface{}{"Branch": branch, "Pkg": name}).
+				Count(&count).Error
+			if count > 0 {
+				pkg = name
+			}
+		}
+		if t.Base.Err == nil && pkg == "" {
+			var names []string
+			for _, p := range AlpineProvidePrefix {
+				names = append(names, p+name)
+			}
+			var rows []TypeDbAlpineRecord
+			t.Base.Err = t.Db.
+				Select("type_db_alpine_records.pkg").
+				Joins("JOIN type_db_alpine_provides ON type_db_alpine_provides.record_id = type_db_alpine_records.id").
+				Where("type_db_alpine_records.branch = ? AND type_db_alpine_provides.name IN ?", branch, names).
+				Order("type_db_alpine_records.provider_priority DESC, type_db_alpine_records.pkg").
+				Limit(1).
+				Find(&rows).Error
+			if len(rows) > 0 {
+				pkg = rows[0].Pkg
+			}
+		}
+		ezlog.Debug().N(prefix).N(name).M(pkg).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return pkg
+}
+
+// OriginPkgs return all packages built from the same origin(o:) as [pkg] in [branch]
+//   - [pkg] itself is included
+//   - Return nil if not found
+func (t *TypeDbAlpine) OriginPkgs(pkg, branch string) (pkgs []string) {
+	prefix := t.MyType + ".OriginPkgs"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		if t.Base.Err == nil {
+			origin := t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Select("origin").
+				Where("branch = ? AND pkg = ? AND origin <> ''", branch, pkg).
+				Limit(1)
+			t.Base.Err = t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Distinct("pkg").
+				Where("branch = ? AND origin = (?)", branch, origin).
+				Order("pkg").
+				Pluck("pkg", &pkgs).Error
+		}
+		ezlog.Debug().N(prefix).N(pkg).M(pkgs).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return pkgs
+}
+
+// SearchProvides search packages by what they provide(p:)
+//   - [option] select match mode(substring, exact, regex) and filters, `Latest` is not used
+//   - Return rows of `SearchProvidesColumns`
+func (t *TypeDbAlpine) SearchProvides(name string, option *TypeDbSearchOption) *[]*[]string {
+	prefix := t.MyType + ".SearchProvides"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		type row struct {
+			Provide string
+			TypeDbAlpineRecord
+		}
+		var rows []row
+		if t.Base.Err == nil {
+			result := t.Db.
+				Model(&TypeDbAlpineRecord{}).
+				Select("type_db_alpine_provides.name AS provide, type_db_alpine_records.pkg, type_db_alpine_records.ver, type_db_alpine_records.repo, type_db_alpine_records.branch, type_db_alpine_records.arch").
+				Joins("JOIN type_db_alpine_provides ON type_db_alpine_provides.record_id = type_db_alpine_records.id")
+			result = option.where(result, "type_db_alpine_records", "type_db_alpine_provides.name", name)
+			t.Base.Err = result.Order("type_db_alpine_records.branch, type_db_alpine_records.repo, type_db_alpine_records.arch, provide").Scan(&rows).Error
+		}
+		if t.Base.Err == nil && option.Regex {
+			var re *regexp.Regexp
+			re, t.Base.Err = regexp.Compile(name)
+			if re != nil {
+				rows = slices.DeleteFunc(rows, func(r row) bool { return !re.MatchString(r.Provide) })
+			}
+		}
+		if t.Base.Err == nil {
+			for _, r := range rows {
+				strArr := []string{r.Provide, r.Pkg, r.Ver, r.Repo, r.Branch, r.Arch}
+				strArrArr = append(strArrArr, &strArr)
+			}
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
@@ -0,0 +1,214 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/J-Siu/go-helper/v2/basestruct"
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+// Default image to distro mapping
+//   - Key is image name, full or last path element, or prefix ending with "*"
+var DbImageDefault = map[string]string{
+	"alpine":    "alpine",
+	"archlinux": "archlinux",
+	"debian":    "debian",
+	"fedora":    "fedora",
+	"ubi*":      "ubi",
+	"ubuntu":    "ubuntu",
+	"wolfi*":    "wolfi",
+}
+
+// Package database registry
+//
+//   - Map image name of Dockerfile FROM line to distro, and distro to [Idb]
+//   - Database is created by registered constructor on first use
+type TypeDbRegistry struct {
+	*basestruct.Base
+
+	Images  map[string]string `json:"Images"`  // image name or prefix -> distro
+	Update  bool              `json:"Update"`  // update database on first use
+	MaxAge  time.Duration     `json:"MaxAge"`  // update database on first use if an index is older, 0 to disable
+	Offline bool              `json:"Offline"` // never download, stale indexes are only recorded in `Stale`
+	Stale   []string          `json:"Stale"`   // stale indexes found in `Offline` mode, distro:branch/repo/arch
+
+	constructors map[string]func() Idb
+	dbs          map[string]Idb
+	refs         map[string][][2]string // distro -> referenced image and tag, see Reference()
+}
+
+// New create registry with `DbImageDefault` mapping
+//   - [images] is additional image names or prefixes of each distro, eg. {"alpine": ["registry.local/base-alpine"]}
+func (t *TypeDbRegistry) New(images map[string][]string, update bool) *TypeDbRegistry {
+	t.Base = new(basestruct.Base)
+	t.Initialized = true
+	t.MyType = "TypeDbRegistry"
+	prefix := t.MyType + ".New"
+
+	t.Images = map[string]string{}
+	for image, distro := range DbImageDefault {
+		t.Images[image] = distro
+	}
+	for distro, list := range images {
+		for _, image := range list {
+			t.Images[strings.ToLower(image)] = strings.ToLower(distro)
+		}
+	}
+	t.Update = update
+	t.constructors = map[string]func() Idb{}
+	t.dbs = map[string]Idb{}
+	t.refs = map[string][][2]string{}
+
+	ezlog.Debug().N(prefix).Lm(t).Out()
+	return t
+}
+
+// Register [constructor] of [distro], it is called on first [TypeDbRegistry.Get]
+func (t *TypeDbRegistry) Register(distro string, constructor func() Idb) *TypeDbRegistry {
+	t.constructors[distro] = constructor
+	return t
+}
+
+// Return registered distros, sorted
+func (t *TypeDbRegistry) Distros() (distros []string) {
+	for distro := range t.constructors {
+		distros = append(distros, distro)
+	}
+	slices.Sort(distros)
+	return distros
+}
+
+// Return distro of [image], eg. "registry.access.redhat.com/ubi9/ubi-minimal" -> "ubi"
+//
+//   - Tag and digest are removed
+//   - Match order: full image name, last path element, longest prefix("*" key)
+//   - A registered distro name matches itself
+//   - Return empty string if not found
+func (t *TypeDbRegistry) Distro(image string) (distro string) {
+	image = strings.ToLower(ImageName(image))
+	base := path.Base(image)
+	if d, ok := t.Images[image]; ok {
+		return d
+	}
+	if d, ok := t.Images[base]; ok {
+		return d
+	}
+	matched := 0
+	for key, d := range t.Images {
+		p, isPrefix := strings.CutSuffix(key, "*")
+		if isPrefix && len(p) > matched && (strings.HasPrefix(image, p) || strings.HasPrefix(base, p)) {
+			distro = d
+			matched = len(p)
+		}
+	}
+	if _, ok := t.constructors[image]; ok && distro == "" {
+		distro = image
+	}
+	return distro
+}
+
+// Reference record [tag] of [image] used by a project, before the database of [image] is used
+//   - Branch of each tag is added to the database on first use, see [Idb.BranchAdd]
+func (t *TypeDbRegistry) Reference(image, tag string) *TypeDbRegistry {
+	distro := t.Distro(image)
+	if ref := [2]string{image, tag}; distro != "" && tag != "" && !slices.Contains(t.refs[distro], ref) {
+		t.refs[distro] = append(t.refs[distro], ref)
+	}
+	return t
+}
+
+// Get return package database of [image] or distro name
+//   - Database is created, connected, branches of referenced tags added and updated(`Update`) on first use
+//   - Database with index older than `MaxAge` is updated, or recorded in `Stale` if `Offline`
+//   - Failed update of stale database is warned and queued, existing database is still used
+//   - Return nil if not supported
+func (t *TypeDbRegistry) Get(image string) Idb {
+	prefix := t.MyType + ".Get"
+	distro := t.Distro(image)
+	if d, ok := t.dbs[distro]; ok {
+		return d
+	}
+	constructor, ok := t.constructors[distro]
+	if !ok {
+		ezlog.Debug().N(prefix).N(image).M("not supported").Out()
+		return nil
+	}
+	ezlog.Debug().N(prefix).N(image).M(distro).Out()
+	d := constructor()
+	var branches []string
+	for _, ref := range t.refs[distro] {
+		branches = append(branches, d.BranchFromImage(ref[0], ref[1]))
+	}
+	if len(branches) > 0 && !t.Offline && d.Err() == nil {
+		d.BranchAdd(branches)
+	}
+	if t.Update && !t.Offline {
+		ezlog.Log().N(distro).M("db update").Out()
+		d.Update()
+	} else if t.MaxAge > 0 && d.Err() == nil {
+		if stale := d.Stale(branches, t.MaxAge); len(stale) > 0 {
+			maxAge := strings.TrimSuffix(strings.TrimSuffix(t.MaxAge.String(), "0s"), "0m") // "24h0m0s" -> "24h"
+			if t.Offline {
+				ezlog.Log().N(distro).M("db stale, older than " + maxAge + ":").M(strings.Join(stale, ", ")).Out()
+				for _, name := range stale {
+					t.Stale = append(t.Stale, distro+":"+name)
+				}
+			} else {
+				ezlog.Log().N(distro).M("db update, older than " + maxAge + ":").M(strings.Join(stale, ", ")).Out()
+				if err := d.Update().ErrClear(); err != nil {
+					ezlog.Log().N(distro).M("db update failed, using existing database").Out()
+					errs.Queue(prefix, err)
+				}
+			}
+		}
+	}
+	errs.Queue(prefix, d.Err())
+	t.dbs[distro] = d
+	return d
+}
+
+// Return image name of [ref] without tag and digest, eg. "registry.local:5000/base:3.20" -> "registry.local:5000/base"
+func ImageName(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndexByte(ref, ':'); i > strings.LastIndexByte(ref, '/') {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// Return tag of image [ref], empty if none, eg. "alpine:edge" -> "edge"
+func ImageTag(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndexByte(ref, ':'); i > strings.LastIndexByte(ref, '/') {
+		return ref[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// End of life of a branch, or a repository of it, from Alpine releases.json
+//   - `Repo` is empty for the branch itself
+//   - `EolDate` is "YYYY-MM-DD", empty if not announced, eg. edge
+//   - `Latest` is set on the "latest_stable" branch
+type TypeDbAlpineRelease struct {
+	Branch  string `json:"Branch" gorm:"primaryKey"`
+	Repo    string `json:"Repo" gorm:"primaryKey"`
+	EolDate string `json:"EolDate"`
+	Latest  bool   `json:"Latest"`
+}
+
+// Alpine releases.json, eg. https://alpinelinux.org/releases.json
+type typeReleases struct {
+	LatestStable    string `json:"latest_stable"` // eg. "v3.22"
+	ReleaseBranches []struct {
+		Branch  string `json:"branch"`
+		EolDate string `json:"eol_date"`
+		Repos   []struct {
+			Name    string `json:"name"`
+			EolDate string `json:"eol_date"`
+		} `json:"repos"`
+	} `json:"release_branches"`
+}
+
+// ReleaseUpdate download and import release metadata(releases.json), replace all branches
+//   - [file] is a local releases.json to import instead
+func (t *TypeDbAlpine) ReleaseUpdate(file string) Idb {
+	prefix := t.MyType + ".ReleaseUpdate"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Connect()
+		}
+		if t.Base.Err == nil && file == "" {
+			if t.ReleasesUrl == "" {
+				t.Base.Err = errors.New(prefix + ": not supported for " + t.Distro)
+			} else {
+				file = path.Join(t.DirDb, "releases.json")
+				_, t.Base.Err = downloadRetry(downloadClient(t.Timeout), t.ReleasesUrl, file, "", "", t.Retry)
+			}
+		}
+		if t.Base.Err == nil {
+			var count int
+			count, t.Base.Err = t.releaseImport(file)
+			ezlog.Debug().N(prefix).N(file).M(count).Out()
+			if t.Base.Err != nil {
+				t.Base.Err = errs.New(prefix, file+": "+t.Base.Err.Error())
+			}
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Import releases.json [file], replace all branches in one transaction
+//   - Return number of branches imported
+func (t *TypeDbAlpine) releaseImport(file string) (count int, err error) {
+	var (
+		data     []byte
+		rows     []TypeDbAlpineRelease
+		releases typeReleases
+	)
+	data, err = os.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(data, &releases)
+	}
+	if err == nil && (releases.LatestStable == "" || len(releases.ReleaseBranches) == 0) {
+		err = errors.New("latest_stable or release_branches not found")
+	}
+	if err == nil {
+		for _, b := range releases.ReleaseBranches {
+			rows = append(rows, TypeDbAlpineRelease{Branch: b.Branch, EolDate: b.EolDate, Latest: b.Branch == releases.LatestStable})
+			for _, r := range b.Repos {
+				rows = append(rows, TypeDbAlpineRelease{Branch: b.Branch, Repo: r.Name, EolDate: r.EolDate})
+			}
+		}
+		err = t.Db.Transaction(func(tx *gorm.DB) (err error) {
+			err = tx.Where("1 = 1").Delete(&TypeDbAlpineRelease{}).Error
+			if err == nil {
+				err = tx.CreateInBatches(rows, 1000).Error
+			}
+			return err
+		})
+	}
+	return len(releases.ReleaseBranches), err
+}
+
+// BranchEol return end of life date("YYYY-MM-DD") of [repo] in [branch]
+//   - Date of the branch is used if [repo] has none, eg. Alpine community end earlier than main
+//   - Return empty string if not announced or release metadata is not imported
+func (t *TypeDbAlpine) BranchEol(branch, repo string) (eol string) {
+	prefix := t.MyType + ".BranchEol"
+	if t.CheckErrInit(prefix) && t.Db != nil {
+		var rows []TypeDbAlpineRelease
+		t.Db.Where("branch = ? AND repo IN ?", branch, []string{"", repo}).Order("repo DESC").Find(&rows)
+		for _, row := range rows {
+			if row.EolDate != "" {
+				eol = row.EolDate
+				break
+			}
+		}
+		ezlog.Debug().N(prefix).N(branch + "/" + repo).M(eol).Out()
+	}
+	return eol
+}
+
+// BranchLatest return newest stable branch, eg. "v3.22"
+//   - Return empty string if release metadata is not imported
+func (t *TypeDbAlpine) BranchLatest() (branch string) {
+	prefix := t.MyType + ".BranchLatest"
+	if t.CheckErrInit(prefix) && t.Db != nil {
+		var row TypeDbAlpineRelease
+		if t.Db.Where("latest = ? AND repo = ?", true, "").Limit(1).Find(&row).Error == nil {
+			branch = row.Branch
+		}
+		ezlog.Debug().N(prefix).M(branch).Out()
+	}
+	return branch
+}
+
+// BranchIsStable return true if [branch] is a stable release branch, eg. "v3.22", not "edge" or "latest-stable"
+func BranchIsStable(branch string) bool {
+	return strings.HasPrefix(branch, "v") && strings.Contains(branch, ".")
+}
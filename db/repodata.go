@@ -0,0 +1,220 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// repomd.xml, only entries used are mapped
+type TypeRpmRepomd struct {
+	Revision string              `xml:"revision"`
+	Data     []TypeRpmRepomdData `xml:"data"`
+}
+
+// One <data> of repomd.xml
+type TypeRpmRepomdData struct {
+	Type     string `xml:"type,attr"`
+	Checksum struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"checksum"`
+	Location struct {
+		Href string `xml:"href,attr"`
+	} `xml:"location"`
+}
+
+// <rpm:entry> of provides, requires and conflicts
+type typeRpmEntry struct {
+	Name  string `xml:"name,attr"`
+	Flags string `xml:"flags,attr"`
+	Epoch string `xml:"epoch,attr"`
+	Ver   string `xml:"ver,attr"`
+	Rel   string `xml:"rel,attr"`
+}
+
+// <package> of primary.xml, only entries used are mapped
+type typeRpmPackage struct {
+	Name    string `xml:"name"`
+	Arch    string `xml:"arch"`
+	Version struct {
+		Epoch string `xml:"epoch,attr"`
+		Ver   string `xml:"ver,attr"`
+		Rel   string `xml:"rel,attr"`
+	} `xml:"version"`
+	Checksum string `xml:"checksum"`
+	Summary  string `xml:"summary"`
+	Packager string `xml:"packager"`
+	Url      string `xml:"url"`
+	Time     struct {
+		Build int64 `xml:"build,attr"`
+	} `xml:"time"`
+	Size struct {
+		Package   int64 `xml:"package,attr"`
+		Installed int64 `xml:"installed,attr"`
+	} `xml:"size"`
+	Format struct {
+		License   string         `xml:"license"`
+		SourceRpm string         `xml:"sourcerpm"`
+		Provides  []typeRpmEntry `xml:"provides>entry"`
+		Requires  []typeRpmEntry `xml:"requires>entry"`
+		Conflicts []typeRpmEntry `xml:"conflicts>entry"`
+	} `xml:"format"`
+}
+
+// rpm dependency flags to operator
+var rpmFlagOp = map[string]string{
+	"EQ": "=",
+	"LT": "<",
+	"LE": "<=",
+	"GT": ">",
+	"GE": ">=",
+}
+
+// Return [TypeDbAlpineDep] of rpm [entry]
+func (t *typeRpmEntry) dep(conflict bool) TypeDbAlpineDep {
+	dep := TypeDbAlpineDep{Name: t.Name, Conflict: conflict}
+	if op, ok := rpmFlagOp[t.Flags]; ok {
+		dep.Op = op
+		dep.Ver = rpmVerJoin(t.Epoch, t.Ver, t.Rel)
+	}
+	return dep
+}
+
+// Return <data> of [dataType] in [repomd], nil if not found
+func (t *TypeRpmRepomd) DataGet(dataType string) *TypeRpmRepomdData {
+	for i := range t.Data {
+		if t.Data[i].Type == dataType {
+			return &t.Data[i]
+		}
+	}
+	return nil
+}
+
+// Parse repomd.xml from [r]
+func rpmRepomdParse(r io.Reader) (repomd *TypeRpmRepomd, err error) {
+	repomd = new(TypeRpmRepomd)
+	err = xml.NewDecoder(r).Decode(repomd)
+	if err == nil && repomd.DataGet("primary") == nil {
+		err = errors.New("repomd.xml: primary not found")
+	}
+	return repomd, err
+}
+
+// Parse primary.xml from [r]
+//
+//   - Packages are decoded one by one, whole file is not loaded
+//   - Only packages of [arch] and "noarch" are kept, eg. i686 multilib in x86_64 is skipped
+//   - `Ver` is [epoch:]version-release, epoch is omitted if 0
+//   - `Origin` is the source rpm name
+//   - Requires with "rpmlib(" prefix are skipped
+func rpmPrimaryParse(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var (
+		decoder = xml.NewDecoder(r)
+		token   xml.Token
+	)
+	for err == nil {
+		token, err = decoder.Token()
+		if err != nil {
+			break
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "package" {
+			continue
+		}
+		var p typeRpmPackage
+		err = decoder.DecodeElement(&p, &element)
+		if err != nil || (p.Arch != arch && p.Arch != "noarch") {
+			continue
+		}
+		record := TypeDbAlpineRecord{
+			Pkg:           p.Name,
+			Branch:        branch,
+			Repo:          repo,
+			Arch:          arch,
+			Ver:           rpmVerJoin(p.Version.Epoch, p.Version.Ver, p.Version.Rel),
+			Epoch:         p.Version.Epoch,
+			VerUpstream:   p.Version.Ver,
+			Release:       p.Version.Rel,
+			Checksum:      p.Checksum,
+			PkgArch:       p.Arch,
+			Size:          p.Size.Package,
+			InstalledSize: p.Size.Installed,
+			Desc:          p.Summary,
+			Url:           p.Url,
+			License:       p.Format.License,
+			Origin:        rpmSourceName(p.Format.SourceRpm),
+			Maintainer:    p.Packager,
+			BuildTime:     p.Time.Build,
+		}
+		if record.Origin == "" {
+			record.Origin = record.Pkg
+		}
+		for _, e := range p.Format.Requires {
+			if !strings.HasPrefix(e.Name, "rpmlib(") {
+				record.Depends = append(record.Depends, TypeDbAlpineDepend{e.dep(false)})
+			}
+		}
+		for _, e := range p.Format.Conflicts {
+			record.Depends = append(record.Depends, TypeDbAlpineDepend{e.dep(true)})
+		}
+		for _, e := range p.Format.Provides {
+			if e.Name != p.Name {
+				record.Provides = append(record.Provides, TypeDbAlpineProvide{e.dep(false)})
+			}
+		}
+		rows = append(rows, record)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return rows, err
+}
+
+// Return source package name of [sourceRpm], eg. "curl-8.6.0-7.fc40.src.rpm" -> "curl"
+func rpmSourceName(sourceRpm string) string {
+	name := strings.TrimSuffix(sourceRpm, ".src.rpm")
+	for range 2 {
+		if i := strings.LastIndexByte(name, '-'); i > 0 {
+			name = name[:i]
+		}
+	}
+	return name
+}
+
+// Read compressed primary.xml from [r]
+//   - Compression(gzip, xz or zstd) is detected from content, not file name
+func rpmPrimaryRead(r io.Reader, branch, repo, arch string) (rows []TypeDbAlpineRecord, err error) {
+	var rc io.ReadCloser
+	rc, err = decompressReader(r)
+	if err == nil {
+		defer rc.Close()
+		rows, err = rpmPrimaryParse(rc, branch, repo, arch)
+	}
+	return rows, err
+}
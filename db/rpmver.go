@@ -0,0 +1,176 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Return true if RPM version [v1] > [v2]
+func RpmVerNewer(v1, v2 string) bool { return RpmVerCmp(v1, v2) > 0 }
+
+// Compare RPM version [v1] and [v2]
+//   - Format: [epoch:]version[-release]
+//   - Release is only compared if both have one
+//   - Return -1, 0, 1 if [v1] is older, same, newer than [v2]
+func RpmVerCmp(v1, v2 string) int {
+	e1, ver1, rel1 := rpmVerSplit(v1)
+	e2, ver2, rel2 := rpmVerSplit(v2)
+	if e1 != e2 {
+		if e1 > e2 {
+			return 1
+		}
+		return -1
+	}
+	if c := rpmVerCmp(ver1, ver2); c != 0 || rel1 == "" || rel2 == "" {
+		return c
+	}
+	return rpmVerCmp(rel1, rel2)
+}
+
+// Split RPM version [v] into epoch, version and release
+//   - Release is after the last "-", empty if none
+func rpmVerSplit(v string) (epoch int, version, release string) {
+	v = strings.TrimSpace(v)
+	version = v
+	if i := strings.IndexByte(version, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(version[:i])
+		version = version[i+1:]
+	}
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		release = version[i+1:]
+		version = version[:i]
+	}
+	return epoch, version, release
+}
+
+// Return rpm version string of [epoch], [version] and [release], epoch is omitted if 0
+func rpmVerJoin(epoch, version, release string) (v string) {
+	if epoch != "" && epoch != "0" {
+		v = epoch + ":"
+	}
+	v += version
+	if release != "" {
+		v += "-" + release
+	}
+	return v
+}
+
+// Compare version or release, port of rpm rpmvercmp()
+//   - Segments are runs of digits or letters, separators are ignored
+//   - "~" sort before anything, "^" sort after end of string but before anything else
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	isAlpha := func(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+	isSep := func(c byte) bool { return !isDigit(c) && !isAlpha(c) && c != '~' && c != '^' }
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && isSep(a[i]) {
+			i++
+		}
+		for j < len(b) && isSep(b[j]) {
+			j++
+		}
+		// tilde
+		if (i < len(a) && a[i] == '~') || (j < len(b) && b[j] == '~') {
+			if i >= len(a) || a[i] != '~' {
+				return 1
+			}
+			if j >= len(b) || b[j] != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		// caret
+		if (i < len(a) && a[i] == '^') || (j < len(b) && b[j] == '^') {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if a[i] != '^' {
+				return 1
+			}
+			if b[j] != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+		// segment
+		isNum := isDigit(a[i])
+		match := isAlpha
+		if isNum {
+			match = isDigit
+		}
+		si, sj := i, j
+		for i < len(a) && match(a[i]) {
+			i++
+		}
+		for j < len(b) && match(b[j]) {
+			j++
+		}
+		segA, segB := a[si:i], b[sj:j]
+		if segB == "" {
+			// numeric segment is newer than alpha segment
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+		if isNum {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i >= len(a):
+		return -1
+	default:
+		return 1
+	}
+}
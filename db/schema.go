@@ -0,0 +1,133 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Applied schema migration
+type TypeDbSchema struct {
+	Version   int       `json:"Version" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"Name"`
+	AppliedAt time.Time `json:"AppliedAt"`
+}
+
+// Schema migration
+type dbMigration struct {
+	version int
+	name    string
+	migrate func(tx *gorm.DB) error
+}
+
+// Schema migrations in order, each is applied once in its own transaction
+//
+//   - Migrations use the current models, eg. migration 1 create tables with all columns of today, so every migration must be idempotent
+//   - Data fix, eg. backfill of migration 6, also run on a fresh database, it must be harmless on empty tables
+//   - Never remove or renumber a migration, append a new one
+//   - New column: append a migration calling AutoMigrate of the model, it only add what is missing
+//   - Database without schema table(before versioning) start from version 0
+var dbMigrations = []dbMigration{
+	{1, "tables", func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&TypeDbAlpineHistory{},
+			&TypeDbAlpineIndex{},
+			&TypeDbAlpineRecord{},
+			&TypeDbAlpineDepend{},
+			&TypeDbAlpineProvide{},
+			&TypeDbAlpineInstallIf{},
+		)
+	}},
+	{2, "full-text index", ftsCreate},
+	{3, "record indexes", func(tx *gorm.DB) (err error) {
+		for _, sql := range []string{
+			// Info, OriginPkgs, PkgResolve, exact Search: pkg without repo or arch
+			"CREATE INDEX IF NOT EXISTS idx_alpine_record_pkg ON type_db_alpine_records (pkg, branch, repo, arch)",
+			// VerGet, idxReplace, histUpdate, Export and filters: one index at a time
+			"CREATE INDEX IF NOT EXISTS idx_alpine_record_index ON type_db_alpine_records (branch, repo, arch, pkg)",
+			// OriginPkgs: packages of the same origin
+			"CREATE INDEX IF NOT EXISTS idx_alpine_record_origin ON type_db_alpine_records (origin, branch)",
+			"ANALYZE",
+		} {
+			if err == nil {
+				err = tx.Exec(sql).Error
+			}
+		}
+		return err
+	}},
+	{4, "security fixes", func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&TypeDbAlpineSecfix{})
+	}},
+	{5, "release branches", func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&TypeDbAlpineRelease{})
+	}},
+	{6, "index fetched at", func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&TypeDbAlpineIndex{})
+		if err == nil {
+			// fetched at least when rows were replaced
+			err = tx.Model(&TypeDbAlpineIndex{}).Where("1 = 1").UpdateColumn("fetched_at", gorm.Expr("updated_at")).Error
+		}
+		return err
+	}},
+	{7, "mirror branch listing", func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&TypeDbAlpineBranch{})
+	}},
+}
+
+// Latest schema version
+func dbSchemaVersion() int { return dbMigrations[len(dbMigrations)-1].version }
+
+// Upgrade schema of [db] in place to the latest version
+//   - Return version before upgrade
+//   - Error if database is newer than this program
+func dbMigrate(db *gorm.DB) (version int, err error) {
+	err = db.AutoMigrate(&TypeDbSchema{})
+	if err == nil {
+		err = db.Model(&TypeDbSchema{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	}
+	if err == nil && version > dbSchemaVersion() {
+		err = errors.New("database schema version " + strconv.Itoa(version) + " is newer than supported " + strconv.Itoa(dbSchemaVersion()) + ", please upgrade")
+	}
+	for _, m := range dbMigrations {
+		if err != nil {
+			break
+		}
+		if m.version <= version {
+			continue
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := m.migrate(tx)
+			if err == nil {
+				err = tx.Create(&TypeDbSchema{Version: m.version, Name: m.name, AppliedAt: time.Now()}).Error
+			}
+			return err
+		})
+	}
+	return version, err
+}
@@ -0,0 +1,164 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// Columns of [TypeDbAlpine.Search] rows
+var SearchColumns = []string{"Pkg", "Ver", "Repo", "Branch", "Arch", "Desc"}
+
+// Columns of [TypeDbAlpine.SearchProvides] rows
+var SearchProvidesColumns = []string{"Provide", "Pkg", "Ver", "Repo", "Branch", "Arch"}
+
+// Maximum number of [TypeDbAlpine.Suggest] result
+const suggestMax = 5
+
+// Search options
+type TypeDbSearchOption struct {
+	Arch   string `json:"Arch"`   // Architecture, empty for all
+	Branch string `json:"Branch"` // Branch, empty for all
+	Exact  bool   `json:"Exact"`  // Match exact name
+	Latest bool   `json:"Latest"` // Newest version per package and branch only
+	Regex  bool   `json:"Regex"`  // Name is regular expression, matched after query
+	Repo   string `json:"Repo"`   // Repository, empty for all
+}
+
+// Add name match and filters to [tx]
+//   - [table] is the table of branch, repo and arch columns
+//   - Regex is not supported by sqlite, all names are returned and matched by caller
+func (t *TypeDbSearchOption) where(tx *gorm.DB, table, column, name string) *gorm.DB {
+	switch {
+	case t.Regex:
+	case t.Exact:
+		tx = tx.Where(column+" = ?", name)
+	default:
+		tx = tx.Where(column+" LIKE ?", "%"+name+"%")
+	}
+	return t.filter(tx, table)
+}
+
+// Add branch, repo and arch filters to [tx], empty filter is skipped
+func (t *TypeDbSearchOption) filter(tx *gorm.DB, table string) *gorm.DB {
+	for _, f := range [][2]string{{"branch", t.Branch}, {"repo", t.Repo}, {"arch", t.Arch}} {
+		if f[1] != "" {
+			tx = tx.Where(table+"."+f[0]+" = ?", f[1])
+		}
+	}
+	return tx
+}
+
+// Return newest version of each package per branch in [rows]
+//   - Version comparison of the distro is used
+//   - Order of first appearance is kept
+func (t *TypeDbAlpine) searchLatest(rows []TypeDbAlpineRecord) (latest []TypeDbAlpineRecord) {
+	index := map[[2]string]int{}
+	for _, r := range rows {
+		key := [2]string{r.Pkg, r.Branch}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(latest)
+			latest = append(latest, r)
+		} else if t.verNewer(r.Ver, latest[i].Ver) {
+			latest[i] = r
+		}
+	}
+	return latest
+}
+
+// Suggest return package names similar to [name], for "did you mean"
+//   - Branch, repo and arch filters of [option] are used
+//   - Names within edit distance of a third of [name](at least 2), or containing [name] case-insensitively
+//   - Sorted by edit distance, up to `suggestMax`
+func (t *TypeDbAlpine) Suggest(name string, option *TypeDbSearchOption) (names []string) {
+	prefix := t.MyType + ".Suggest"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var pkgs []string
+		if t.Base.Err == nil {
+			t.Base.Err = option.filter(t.Db.Model(&TypeDbAlpineRecord{}), "type_db_alpine_records").
+				Distinct("pkg").
+				Pluck("pkg", &pkgs).Error
+		}
+		if t.Base.Err == nil {
+			type suggestion struct {
+				name     string
+				distance int
+			}
+			var (
+				found   []suggestion
+				lower   = strings.ToLower(name)
+				maxDist = max(2, len(name)/3)
+			)
+			for _, pkg := range pkgs {
+				d := editDistance(lower, strings.ToLower(pkg))
+				if d <= maxDist || strings.Contains(strings.ToLower(pkg), lower) {
+					found = append(found, suggestion{pkg, d})
+				}
+			}
+			slices.SortFunc(found, func(a, b suggestion) int {
+				return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+			})
+			for _, s := range found[:min(len(found), suggestMax)] {
+				names = append(names, s.name)
+			}
+		}
+		ezlog.Debug().N(prefix).N(name).M(names).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return names
+}
+
+// Return Levenshtein distance between [a] and [b]
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,262 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/J-Siu/go-helper/v2/errs"
+	"github.com/J-Siu/go-helper/v2/ezlog"
+	"gorm.io/gorm"
+)
+
+// One security fix of a package, from Alpine secdb `secfixes`
+//   - `Pkg` is origin(source) package name
+//   - `Ver` is the first version with the fix, "0" means never affected
+type TypeDbAlpineSecfix struct {
+	ID     uint   `json:"-" gorm:"primaryKey"`
+	Pkg    string `json:"Pkg" gorm:"index:idx_alpine_secfix"`
+	Branch string `json:"Branch" gorm:"index:idx_alpine_secfix"`
+	Repo   string `json:"Repo"`
+	Ver    string `json:"Ver"`
+	Cve    string `json:"Cve"` // CVE or other advisory ID, eg. CVE-2024-6119, GHSA-xxxx
+}
+
+// Alpine secdb file, eg. https://secdb.alpinelinux.org/v3.20/main.json
+type typeSecdb struct {
+	DistroVersion string `json:"distroversion"` // branch, eg. "v3.20"
+	RepoName      string `json:"reponame"`
+	Packages      []struct {
+		Pkg struct {
+			Name     string              `json:"name"`
+			Secfixes map[string][]string `json:"secfixes"` // fixed version -> IDs
+		} `json:"pkg"`
+	} `json:"packages"`
+}
+
+// SecdbUpdate download and import security database(secdb) of all branches and repositories
+//
+//   - [files] are local secdb files to import instead, eg. v3.20/main.json
+//   - Branch and repository are read from the file, `BranchRolling` and first `RepoDefault` if absent
+//   - Fixes of each branch/repository are replaced as a whole, failed one is untouched
+func (t *TypeDbAlpine) SecdbUpdate(files []string) Idb {
+	prefix := t.MyType + ".SecdbUpdate"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Connect()
+		}
+		var failed []string
+		if t.Base.Err == nil && len(files) == 0 {
+			files, failed = t.secdbDownload()
+		}
+		for _, f := range files {
+			if t.Base.Err != nil {
+				break
+			}
+			count, err := t.secdbImport(f)
+			ezlog.Debug().N(prefix).N(f).M(count).Out()
+			if err != nil {
+				failed = append(failed, f)
+				errs.Queue(prefix, errors.New(f+": "+err.Error()))
+			}
+		}
+		if t.Base.Err == nil && len(failed) > 0 {
+			t.Base.Err = errs.New(prefix, "failed: "+strings.Join(failed, ", "))
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return t
+}
+
+// Download secdb file of each branch with indexes in database and each repository into cache
+//   - Return downloaded files and failed branch/repo, error is queued in errs
+//   - Branch "latest-stable" is mapped to the actual stable branch, see branchStable()
+//   - Repository "testing" has no secdb
+func (t *TypeDbAlpine) secdbDownload() (files, failed []string) {
+	prefix := t.MyType + ".secdbDownload"
+	client := downloadClient(t.Timeout)
+	for _, branch := range t.secdbBranches() {
+		for _, repo := range t.Repository {
+			if repo == "testing" {
+				continue
+			}
+			var (
+				err     error
+				errMsgs []string
+				file    = path.Join(t.DirDb, "secdb", branch, repo+".json")
+				urlPath = strings.NewReplacer("{branch}", branch, "{repo}", repo).Replace(t.SecdbPath)
+			)
+			err = os.MkdirAll(path.Dir(file), os.ModePerm)
+			for _, mirror := range t.SecdbMirrors {
+				if err != nil {
+					break
+				}
+				var urlSecdb string
+				urlSecdb, err = url.JoinPath(mirror, urlPath)
+				if err == nil {
+					_, err = downloadRetry(client, urlSecdb, file, "", "", t.Retry)
+				}
+				if err == nil {
+					break
+				}
+				errMsgs = append(errMsgs, err.Error())
+				err = nil
+			}
+			if err == nil && len(errMsgs) == len(t.SecdbMirrors) {
+				err = errors.New("all mirrors failed: " + strings.Join(errMsgs, "; "))
+			}
+			if err == nil {
+				files = append(files, file)
+			} else {
+				failed = append(failed, branch+"/"+repo)
+			}
+			errs.Queue(prefix, err)
+		}
+	}
+	return files, failed
+}
+
+// Return branches with indexes in database, `Branch` if none, "latest-stable" mapped to the actual stable branch
+//   - Branch which cannot be mapped is skipped with error queued
+func (t *TypeDbAlpine) secdbBranches() (branches []string) {
+	prefix := t.MyType + ".secdbBranches"
+	var indexed []string
+	t.Db.Model(&TypeDbAlpineIndex{}).Distinct("branch").Order("branch").Pluck("branch", &indexed)
+	if len(indexed) == 0 {
+		indexed = t.Branch
+	}
+	for _, branch := range indexed {
+		stable := t.branchStable(branch, t.Branches)
+		if stable == "" {
+			errs.Queue(prefix, errors.New(branch+" not mapped to a stable branch, import release metadata with db releases"))
+			continue
+		}
+		if !slices.Contains(branches, stable) {
+			branches = append(branches, stable)
+		}
+	}
+	ezlog.Debug().N(prefix).M(branches).Out()
+	return branches
+}
+
+// Import secdb [file], replace fixes of its branch and repository in one transaction
+//   - Return number of fixes imported
+func (t *TypeDbAlpine) secdbImport(file string) (count int, err error) {
+	var (
+		data  []byte
+		fixes []TypeDbAlpineSecfix
+		secdb typeSecdb
+	)
+	data, err = os.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(data, &secdb)
+	}
+	if err == nil {
+		if secdb.DistroVersion == "" {
+			secdb.DistroVersion = t.BranchRolling
+		}
+		if secdb.RepoName == "" && len(t.RepoDefault) > 0 {
+			secdb.RepoName = t.RepoDefault[0]
+		}
+		if secdb.DistroVersion == "" || secdb.RepoName == "" {
+			err = errors.New("distroversion or reponame not found")
+		}
+	}
+	if err == nil {
+		for _, p := range secdb.Packages {
+			for ver, ids := range p.Pkg.Secfixes {
+				for _, line := range ids {
+					for _, id := range strings.Fields(line) {
+						fixes = append(fixes, TypeDbAlpineSecfix{Pkg: p.Pkg.Name, Branch: secdb.DistroVersion, Repo: secdb.RepoName, Ver: ver, Cve: id})
+					}
+				}
+			}
+		}
+		err = t.Db.Transaction(func(tx *gorm.DB) (err error) {
+			err = tx.Where("branch = ? AND repo = ?", secdb.DistroVersion, secdb.RepoName).Delete(&TypeDbAlpineSecfix{}).Error
+			if err == nil && len(fixes) > 0 {
+				err = tx.CreateInBatches(fixes, 1000).Error
+			}
+			return err
+		})
+	}
+	return len(fixes), err
+}
+
+// Secfixes return IDs of security fixes of [pkg] in [branch], fixed after [verFrom] up to [verTo]
+//   - [pkg] is origin(source) package name
+//   - [branch] "latest-stable" is mapped to the actual stable branch, newest branch in security database if release metadata is not imported
+//   - Version comparison of the distro is used
+//   - Return in order of fixed version
+func (t *TypeDbAlpine) Secfixes(pkg, branch, verFrom, verTo string) (ids []string) {
+	prefix := t.MyType + ".Secfixes"
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Base.Err = errors.New("database not connected")
+		}
+		var fixes []TypeDbAlpineSecfix
+		if t.Base.Err == nil {
+			branch = t.branchStable(branch, func() (branches []string) {
+				t.Db.Model(&TypeDbAlpineSecfix{}).Distinct("branch").Pluck("branch", &branches)
+				return branches
+			})
+			t.Base.Err = t.Db.
+				Where("pkg = ? AND branch = ?", pkg, branch).
+				Order("cve").
+				Find(&fixes).Error
+		}
+		if t.Base.Err == nil {
+			fixes = slices.DeleteFunc(fixes, func(f TypeDbAlpineSecfix) bool {
+				return f.Ver == "0" || !t.verNewer(f.Ver, verFrom) || t.verNewer(f.Ver, verTo)
+			})
+			slices.SortStableFunc(fixes, func(a, b TypeDbAlpineSecfix) int {
+				switch {
+				case t.verNewer(a.Ver, b.Ver):
+					return 1
+				case t.verNewer(b.Ver, a.Ver):
+					return -1
+				}
+				return cmp.Compare(a.Cve, b.Cve)
+			})
+			for _, f := range fixes {
+				if !slices.Contains(ids, f.Cve) {
+					ids = append(ids, f.Cve)
+				}
+			}
+		}
+		ezlog.Debug().N(prefix).N(pkg).M(ids).Out()
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return ids
+}
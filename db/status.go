@@ -0,0 +1,110 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/J-Siu/go-helper/v2/ezlog"
+)
+
+// Stale return indexes of `Branch` and [branches] not fetched within [maxAge]
+//   - Return name(branch/repo/arch) of stale indexes, branch name if it has no index
+func (t *TypeDbAlpine) Stale(branches []string, maxAge time.Duration) (names []string) {
+	prefix := t.MyType + ".Stale"
+	if t.CheckErrInit(prefix) && t.Db != nil {
+		cutoff := time.Now().Add(-maxAge)
+		for _, branch := range slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(t.Branch), branches...)))) {
+			var indexes []TypeDbAlpineIndex
+			t.Db.Where("branch = ?", branch).Order("repo, arch").Find(&indexes)
+			if len(indexes) == 0 {
+				names = append(names, branch)
+			}
+			for _, index := range indexes {
+				if index.FetchedAt.Before(cutoff) {
+					names = append(names, index.Name())
+				}
+			}
+		}
+		ezlog.Debug().N(prefix).M(names).Out()
+	}
+	return names
+}
+
+// Status return one row per index in database: branch, repo, arch, rows, fetched at, age, stale
+//   - Stale if not fetched within [maxAge], never if [maxAge] is 0
+func (t *TypeDbAlpine) Status(maxAge time.Duration) *[]*[]string {
+	prefix := t.MyType + ".Status"
+	var (
+		strArrArr []*[]string
+	)
+	if t.CheckErrInit(prefix) {
+		ezlog.Debug().N(prefix).TxtStart().Out()
+		if t.Db == nil {
+			t.Connect()
+		}
+		var indexes []TypeDbAlpineIndex
+		if t.Base.Err == nil {
+			t.Base.Err = t.Db.Order("branch, repo, arch").Find(&indexes).Error
+		}
+		now := time.Now()
+		for _, index := range indexes {
+			var (
+				age       = "never"
+				fetchedAt = "never"
+				stale     = "No"
+			)
+			if !index.FetchedAt.IsZero() {
+				age = ageString(now.Sub(index.FetchedAt))
+				fetchedAt = index.FetchedAt.UTC().Format(time.RFC3339)
+			}
+			if maxAge > 0 && index.FetchedAt.Before(now.Add(-maxAge)) {
+				stale = "Yes"
+			}
+			strArr := []string{index.Branch, index.Repo, index.Arch, strconv.Itoa(index.Rows), fetchedAt, age, stale}
+			strArrArr = append(strArrArr, &strArr)
+		}
+		ezlog.Debug().N(prefix).TxtEnd().Out()
+	}
+	return &strArrArr
+}
+
+// Return [d] in days, hours and minutes, eg. "3d4h", "2h5m", "7m"
+func ageString(d time.Duration) string {
+	var (
+		days    = int(d.Hours()) / 24
+		hours   = int(d.Hours()) % 24
+		minutes = int(d.Minutes()) % 60
+	)
+	switch {
+	case days > 0:
+		return strconv.Itoa(days) + "d" + strconv.Itoa(hours) + "h"
+	case hours > 0:
+		return strconv.Itoa(hours) + "h" + strconv.Itoa(minutes) + "m"
+	}
+	return strconv.Itoa(minutes) + "m"
+}
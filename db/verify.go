@@ -0,0 +1,194 @@
+/*
+The MIT License (MIT)
+
+Copyright © 2025 John, Sing Dao, Siu <john.sd.siu@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"embed"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Built-in trusted public keys, used when a key is not found in the key directory
+//   - Keys are `keys/<key name>`, same name as in the alpine-keys package
+//
+//go:embed keys
+var keysEmbed embed.FS
+
+const (
+	apkIndexSignRsa    = apkIndexSignPrefix + "RSA."
+	apkIndexSignRsa256 = apkIndexSignPrefix + "RSA256."
+)
+
+// Verify APKINDEX.tar.gz signature
+//
+//   - [data] is the whole APKINDEX.tar.gz
+//   - [idx] is [data] read by [apkIndexTgzRead]
+//   - The signature covers the 2nd gzip stream(DESCRIPTION + APKINDEX)
+//   - Public key is `<dirKeys>/<key name>`, key name is taken from the .SIGN.RSA.<key name> entry
+//   - Key name is untrusted, it must be a regular file directly inside [dirKeys]
+func apkIndexVerify(data []byte, idx *TypeApkIndexTgz, dirKeys string) (err error) {
+	var (
+		ctrl    []byte
+		hash    crypto.Hash
+		keyName string
+		pub     *rsa.PublicKey
+	)
+	switch {
+	case idx.SignName == "":
+		err = errors.New("signature not found")
+	case strings.HasPrefix(idx.SignName, apkIndexSignRsa256):
+		hash = crypto.SHA256
+		keyName = strings.TrimPrefix(idx.SignName, apkIndexSignRsa256)
+	case strings.HasPrefix(idx.SignName, apkIndexSignRsa):
+		hash = crypto.SHA1
+		keyName = strings.TrimPrefix(idx.SignName, apkIndexSignRsa)
+	default:
+		err = errors.New("signature type not supported: " + idx.SignName)
+	}
+	if err == nil {
+		ctrl, err = apkIndexCtrl(data)
+	}
+	if err == nil {
+		pub, err = rsaPubKeyGet(dirKeys, keyName)
+	}
+	if err == nil {
+		var digest []byte
+		if hash == crypto.SHA256 {
+			sum := sha256.Sum256(ctrl)
+			digest = sum[:]
+		} else {
+			sum := sha1.Sum(ctrl)
+			digest = sum[:]
+		}
+		err = rsa.VerifyPKCS1v15(pub, hash, digest, idx.Sign)
+		if err != nil {
+			err = errors.New("signature verification failed with key " + keyName)
+		}
+	}
+	return err
+}
+
+// Return the 2nd gzip stream(DESCRIPTION + APKINDEX) of APKINDEX.tar.gz
+func apkIndexCtrl(data []byte) (ctrl []byte, err error) {
+	var gz *gzip.Reader
+	// bytes.Reader is a io.ByteReader, gzip will not read ahead
+	r := bytes.NewReader(data)
+	gz, err = gzip.NewReader(r)
+	if err == nil {
+		gz.Multistream(false)
+		_, err = io.Copy(io.Discard, gz)
+	}
+	if err == nil {
+		ctrl = data[len(data)-r.Len():]
+		if len(ctrl) == 0 {
+			err = errors.New("index stream not found")
+		}
+	}
+	return ctrl, err
+}
+
+// Return true if [dirKeys] or built-in keys has a key, otherwise every index would fail verification
+func rsaPubKeyAny(dirKeys string) bool {
+	var entries []os.DirEntry
+	entries, _ = os.ReadDir(dirKeys)
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			return true
+		}
+	}
+	matches, _ := fs.Glob(keysEmbed, "keys/*.rsa.pub")
+	return len(matches) > 0
+}
+
+// Return trusted public key [keyName] in [dirKeys], or built-in key if not in [dirKeys]
+//   - [keyName] come from the index, path separator and ".." are rejected
+//   - Only regular file directly inside [dirKeys] is accepted, symlink is not followed
+func rsaPubKeyGet(dirKeys, keyName string) (pub *rsa.PublicKey, err error) {
+	var (
+		b    []byte
+		info os.FileInfo
+	)
+	if keyName == "" || strings.ContainsAny(keyName, `/\`) || strings.Contains(keyName, "..") || keyName != filepath.Base(keyName) {
+		return nil, errors.New("invalid key name: " + keyName)
+	}
+	info, err = os.Lstat(filepath.Join(dirKeys, keyName))
+	if os.IsNotExist(err) {
+		if b, _ = keysEmbed.ReadFile("keys/" + keyName); b != nil {
+			return rsaPubKeyParse(b, keyName)
+		}
+	}
+	if err == nil && !info.Mode().IsRegular() {
+		err = errors.New("key is not a regular file: " + keyName)
+	}
+	if err == nil {
+		pub, err = rsaPubKeyRead(filepath.Join(dirKeys, keyName))
+	}
+	return pub, err
+}
+
+// Read PEM encoded RSA public key from [filepath]
+func rsaPubKeyRead(filepath string) (pub *rsa.PublicKey, err error) {
+	var b []byte
+	b, err = os.ReadFile(filepath)
+	if err == nil {
+		pub, err = rsaPubKeyParse(b, filepath)
+	}
+	return pub, err
+}
+
+// Parse PEM encoded RSA public key [b], [name] is used in error
+func rsaPubKeyParse(b []byte, name string) (pub *rsa.PublicKey, err error) {
+	var (
+		block *pem.Block
+		key   any
+		ok    bool
+	)
+	block, _ = pem.Decode(b)
+	if block == nil {
+		err = errors.New(name + ": not a PEM file")
+	}
+	if err == nil {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err == nil {
+		pub, ok = key.(*rsa.PublicKey)
+		if !ok {
+			err = errors.New(name + ": not a RSA public key")
+		}
+	}
+	return pub, err
+}
@@ -32,8 +32,12 @@ import (
 var (
 	Conf         lib.TypeConf
 	Flag         lib.TypeFlag
+	FlagCheck    lib.TypeFlagCheck
 	FlagUpdate   lib.TypeFlagUpdate
 	FlagDbSearch lib.TypeFlagDbSearch
+	FlagDbDeps   lib.TypeFlagDbDeps
+	FlagDbExport lib.TypeFlagDbExport
 
-	Db db.Idb
+	Db         db.Idb
+	DbRegistry db.TypeDbRegistry
 )
@@ -5,8 +5,10 @@ go 1.26.3
 require (
 	github.com/J-Siu/go-helper/v2 v2.8.4
 	github.com/go-git/go-git/v6 v6.0.0-alpha.4
+	github.com/klauspost/compress v1.20.1
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/viper v1.21.0
+	github.com/ulikunitz/xz v0.5.17
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.31.1
 )
@@ -37,11 +37,14 @@ import (
 )
 
 type TypeChangeLogProperty struct {
-	Dir           *string `json:"Dir"`
-	FileChangeLog *string `json:"FileChangeLog"` // CHANGELOG.md filename
-	Pkg           *string `json:"Pkg"`
-	VerCurr       *string `json:"VerCurr"`
-	VerNew        *string `json:"VerNew"`
+	Dir           *string   `json:"Dir"`
+	FileChangeLog *string   `json:"FileChangeLog"` // CHANGELOG.md filename
+	Pkg           *string   `json:"Pkg"`
+	VerCurr       *string   `json:"VerCurr"`
+	VerNew        *string   `json:"VerNew"`
+	Secfixes      *[]string `json:"Secfixes"` // Security fix IDs(CVE) of `VerNew`, optional
+
+	VerNewer func(v1, v2 string) bool `json:"-"` // Version comparison of the package distro, default VerNewer
 }
 
 type TypeChangeLog struct {
@@ -85,7 +88,11 @@ func (t *TypeChangeLog) Update() *TypeChangeLog {
 	prefix := t.MyType + ".Update"
 	if t.CheckErrInit(prefix) {
 		var contentNew []string
-		if VerNewer(*t.VerNew, *t.VerCurr)  {
+		verNewer := t.VerNewer
+		if verNewer == nil {
+			verNewer = VerNewer
+		}
+		if verNewer(*t.VerNew, *t.VerCurr) {
 			ezlog.Debug().N(prefix).N(t.Pkg).M(t.VerCurr).M("->").M(t.VerNew).Out()
 			for _, line := range *t.Content {
 				ezlog.Debug().N(prefix).M(&line).Out()
@@ -98,6 +105,9 @@ func (t *TypeChangeLog) Update() *TypeChangeLog {
 			}
 			contentNew = append(contentNew, "- "+*t.VerNew)
 			contentNew = append(contentNew, "  - Auto update to "+*t.VerNew)
+			if t.Secfixes != nil && len(*t.Secfixes) > 0 {
+				contentNew = append(contentNew, "  - Fix "+strings.Join(*t.Secfixes, ", "))
+			}
 			t.Content = &contentNew
 			t.write()
 		} else {
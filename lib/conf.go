@@ -23,6 +23,9 @@ THE SOFTWARE.
 package lib
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/J-Siu/go-helper/v2/basestruct"
 	"github.com/J-Siu/go-helper/v2/errs"
 	"github.com/J-Siu/go-helper/v2/ezlog"
@@ -39,6 +42,15 @@ var ConfDefault = TypeConf{
 	FileChangeLog: "CHANGELOG.md",
 
 	AlpineBranch: []string{"latest-stable", "edge"},
+	AlpineKeys:   "/etc/apk/keys",
+	AlpineVerify: true,
+
+	AlpineMirrors: []string{"http://dl-cdn.alpinelinux.org/alpine"},
+	AlpineRetry:   2,
+	AlpineTimeout: 30,
+
+	DbConcurrency: 4,
+	DbMaxAge:      24,
 
 	TagReadmeLogStart: "<!--CHANGE-LOG-START-->",
 	TagReadmeLogEnd:   "<!--CHANGE-LOG-END-->",
@@ -54,7 +66,46 @@ type TypeConf struct {
 	FileLicense   string `json:"FileLicense"` // Filename, not full path, of readme file. Default: LICENSE
 	FileChangeLog string `json:"FileReadme"`  // Filename, not full path, of readme file. Default: README.md
 
-	AlpineBranch []string `json:"AlpineBranch"`
+	AlpineArch   []string `json:"AlpineArch"`   // Alpine architecture or Docker platform, eg. x86_64, linux/amd64
+	AlpineBranch []string `json:"AlpineBranch"` // Branches always in database, branches of projects are added. Default: latest-stable, edge
+	AlpineKeys   string   `json:"AlpineKeys"`   // Directory of trusted Alpine public keys, built-in keys are used if not found. Default: /etc/apk/keys
+	AlpineVerify bool     `json:"AlpineVerify"` // Verify APKINDEX signature. Default: true
+
+	AlpineMirrors []string `json:"AlpineMirrors"` // Base URL of mirrors, in order of preference. Default: http://dl-cdn.alpinelinux.org/alpine
+	AlpineRetry   int      `json:"AlpineRetry"`   // Retry per mirror. Default: 2
+	AlpineTimeout int      `json:"AlpineTimeout"` // Timeout per request in second. Default: 30
+
+	AlpineSecdbMirrors []string `json:"AlpineSecdbMirrors"` // Base URL of Alpine security database mirrors. Default: https://secdb.alpinelinux.org
+	AlpineReleasesUrl  string   `json:"AlpineReleasesUrl"`  // URL of Alpine release metadata. Default: https://alpinelinux.org/releases.json
+
+	DebianArch            []string `json:"DebianArch"`            // Debian and Ubuntu architecture or Docker platform, eg. amd64, linux/amd64. Default: amd64, arm64
+	DebianBranch          []string `json:"DebianBranch"`          // Debian suites(codenames). Default: bookworm, trixie
+	DebianMirrors         []string `json:"DebianMirrors"`         // Debian mirrors. Default: http://deb.debian.org/debian
+	DebianSecurityMirrors []string `json:"DebianSecurityMirrors"` // Debian security mirrors, <suite>-security. Default: http://deb.debian.org/debian-security
+	UbuntuBranch          []string `json:"UbuntuBranch"`          // Ubuntu suites(codenames). Default: jammy, noble
+	UbuntuMirrors         []string `json:"UbuntuMirrors"`         // Ubuntu mirrors. Default: http://archive.ubuntu.com/ubuntu
+
+	RpmArch       []string `json:"RpmArch"`       // Fedora and UBI architecture or Docker platform, eg. x86_64, linux/amd64. Default: aarch64, x86_64
+	FedoraBranch  []string `json:"FedoraBranch"`  // Fedora releases. Default: 43, 44
+	FedoraMirrors []string `json:"FedoraMirrors"` // Fedora mirrors. Default: https://dl.fedoraproject.org/pub/fedora/linux
+	UbiBranch     []string `json:"UbiBranch"`     // Red Hat UBI major releases. Default: 9
+	UbiMirrors    []string `json:"UbiMirrors"`    // Red Hat UBI mirrors. Default: https://cdn-ubi.redhat.com/content/public/ubi/dist
+
+	WolfiArch    []string `json:"WolfiArch"`    // Wolfi architecture or Docker platform, eg. x86_64, linux/amd64. Default: aarch64, x86_64
+	WolfiMirrors []string `json:"WolfiMirrors"` // Wolfi mirrors. Default: https://packages.wolfi.dev
+	WolfiVerify  bool     `json:"WolfiVerify"`  // Verify Wolfi APKINDEX signature, wolfi-signing.rsa.pub must be in AlpineKeys. Default: false
+
+	ArchLinuxMirrors []string `json:"ArchLinuxMirrors"` // Arch Linux mirrors. Default: https://geo.mirror.pkgbuild.com
+
+	DbConcurrency int `json:"DbConcurrency"` // Number of parallel index download. Default: 4
+	DbMaxAge      int `json:"DbMaxAge"`      // Hours before indexes are refreshed by check and update, 0 to disable. Default: 24
+
+	// Additional FROM image names of each distro, key is distro, eg. {"alpine": ["registry.local/base-alpine"]}
+	//   - Image ending with "*" is a prefix, eg. "registry.local/base-*"
+	DistroImage map[string][]string `json:"DistroImage"`
+
+	// Target architectures per project, key is project directory name(lowercase). Default: architectures of FROM distro, eg. AlpineArch
+	ProjectArch map[string][]string `json:"ProjectArch"`
 
 	// TODO: Change following to array
 	TagReadmeLogStart string `json:"ReadmeLogStart"` // Default: <!--CHANGE-LOG-START-->
@@ -106,13 +157,33 @@ func (t *TypeConf) setDefault() *TypeConf {
 	t.DirRepo = ConfDefault.DirRepo
 	t.FileLicense = ConfDefault.FileLicense
 	t.FileChangeLog = ConfDefault.FileChangeLog
+	t.AlpineArch = ConfDefault.AlpineArch
 	t.AlpineBranch = ConfDefault.AlpineBranch
+	t.AlpineKeys = ConfDefault.AlpineKeys
+	t.AlpineVerify = ConfDefault.AlpineVerify
+	t.DbConcurrency = ConfDefault.DbConcurrency
+	t.DbMaxAge = ConfDefault.DbMaxAge
+	t.AlpineMirrors = ConfDefault.AlpineMirrors
+	t.AlpineRetry = ConfDefault.AlpineRetry
+	t.AlpineTimeout = ConfDefault.AlpineTimeout
 	t.TagReadmeLogEnd = ConfDefault.TagReadmeLogEnd
 	t.TagReadmeLogStart = ConfDefault.TagReadmeLogStart
 	return t
 }
 
+// Return target architectures of project in [dir]
+//   - Docker platform is kept, it is converted by the package database of the project
+//   - Return nil if project is not in `ProjectArch`, architectures of the FROM distro are used, eg. `AlpineArch`, `DebianArch`
+func (t *TypeConf) ProjectArchGet(dir string) (arch []string) {
+	dirAbs, err := filepath.Abs(dir)
+	if err != nil {
+		dirAbs = dir
+	}
+	return t.ProjectArch[strings.ToLower(filepath.Base(dirAbs))]
+}
+
 func (t *TypeConf) expand() *TypeConf {
+	t.AlpineKeys = file.TildeEnvExpand(t.AlpineKeys)
 	t.DirCache = file.TildeEnvExpand(t.DirCache)
 	t.FileConf = file.TildeEnvExpand(t.FileConf)
 	return t
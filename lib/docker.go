@@ -28,7 +28,9 @@ import (
 	"errors"
 	"os"
 	"path"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/J-Siu/go-auto-docker/db"
 	"github.com/J-Siu/go-helper/v2/basestruct"
@@ -39,6 +41,9 @@ import (
 	"github.com/J-Siu/go-helper/v2/str"
 )
 
+// Characters allowed in package name
+const pkgNameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.+:"
+
 type TypeDocker struct {
 	*basestruct.Base
 
@@ -46,14 +51,29 @@ type TypeDocker struct {
 	Dir      string    `json:"dir,omitempty"`
 	FilePath string    `json:"file_path,omitempty"`
 
-	Distro string   `json:"distro,omitempty"`
-	Branch string   `json:"branch,omitempty"`
-	Repo   []string `json:"repo,omitempty"`
-	Pkg    string   `json:"pkg,omitempty"`
-	PkgRun string   `json:"pkg_run,omitempty"` // The <Pkg=*> string in RUN line
+	Distro    string   `json:"distro,omitempty"`
+	Tag       string   `json:"tag,omitempty"`        // image tag in FROM line
+	Branch    string   `json:"branch,omitempty"`     // branch of `Tag` in database, eg. "bookworm-slim" -> "bookworm"
+	BranchOld string   `json:"branch_old,omitempty"` // `Branch` before BumpBranch(), empty if not bumped
+	Repo      []string `json:"repo,omitempty"`
+	Pkg       string   `json:"pkg,omitempty"`
+	PkgDb     string   `json:"pkg_db,omitempty"`  // Real package name of `Pkg` in database, `Pkg` can be virtual(provides)
+	PkgRun    string   `json:"pkg_run,omitempty"` // The <Pkg=*> string in RUN line, "=" is `PkgVerSep`
+
+	PkgVerSep string `json:"pkg_ver_sep,omitempty"` // separator of package name and version in RUN line, eg. "=" for apk, "-" for dnf
+
+	VerCurr string                 `json:"ver_curr,omitempty"`
+	VerNew  string                 `json:"ver_new,omitempty"`
+	RepoNew string                 `json:"repo_new,omitempty"` // repository of `VerNew`
+	PkgInfo *db.TypeDbAlpineRecord `json:"pkg_info,omitempty"` // database record of `VerNew`
+
+	Arch     []string          `json:"arch,omitempty"`      // target architectures
+	VerArch  map[string]string `json:"ver_arch,omitempty"`  // newest version per target architecture
+	HeldBack []string          `json:"held_back,omitempty"` // target architectures with an older version than `VerNew`
+
+	PkgSub map[string]string `json:"pkg_sub,omitempty"` // pinned subpackages of the same origin, name -> <name=*> in RUN line
+	SubLag []string          `json:"sub_lag,omitempty"` // subpackages without `VerNew` on target architectures of the package
 
-	VerCurr string `json:"ver_curr,omitempty"`
-	VerNew  string `json:"ver_new,omitempty"`
 	db      db.Idb
 	updated bool
 
@@ -61,18 +81,19 @@ type TypeDocker struct {
 	Verbose bool `json:"verbose,omitempty"`
 }
 
-// Assuming branch = main + community
-//
 // Read and extract information from Dockerfile
-func (t *TypeDocker) New(dir *string, db db.Idb, debug, verbose bool) *TypeDocker {
+//   - [dbGet] return package database of FROM distro, nil if not supported
+//   - [arch] is target architectures or Docker platforms, use all database architectures if empty
+//   - Repositories are the database defaults, eg. Alpine main + community
+//   - All fields are reset, eg. [TypeDocker] of the project is reused for its cache copy
+func (t *TypeDocker) New(dir *string, dbGet func(distro string) db.Idb, arch []string, debug, verbose bool) *TypeDocker {
+	*t = TypeDocker{}
 	t.Base = new(basestruct.Base)
 	t.Initialized = true
 	t.MyType = "TypeDocker"
 	prefix := t.MyType + ".New"
 
-	t.db = db
 	t.Verbose = verbose
-	t.Repo = []string{"main", "community"}
 	t.Dir = *dir
 	t.FilePath = path.Join(t.Dir, "Dockerfile")
 	if !file.IsRegularFile(t.FilePath) {
@@ -81,6 +102,27 @@ func (t *TypeDocker) New(dir *string, db db.Idb, debug, verbose bool) *TypeDocke
 	if t.Err == nil {
 		t.read().extract()
 	}
+	if t.Err == nil && t.Distro != "" {
+		t.db = dbGet(t.Distro)
+		if t.db == nil {
+			t.Err = errors.New(*dir + " FROM distro " + t.Distro + " not supported")
+			errs.Queue(prefix, t.Err)
+		}
+	}
+	if t.Err == nil && t.db != nil {
+		t.PkgVerSep = t.db.PkgVerSep()
+		t.Branch = t.db.BranchFromImage(t.Distro, t.Tag)
+		t.Repo = append(t.db.RepoGet(), t.Repo...)
+		for _, a := range arch {
+			t.Arch = append(t.Arch, t.db.ArchFromPlatform(a))
+		}
+		if len(t.Arch) == 0 {
+			t.Arch = t.db.ArchGet()
+		}
+	}
+	if t.Err == nil && t.Pkg != "" && t.Branch != "" {
+		t.resolve()
+	}
 	ezlog.Debug().N(prefix).Lm(t).Out()
 	if t.Err == nil {
 		if t.Branch == "" {
@@ -106,8 +148,167 @@ func (t *TypeDocker) New(dir *string, db db.Idb, debug, verbose bool) *TypeDocke
 	return t
 }
 
+// Scan read FROM and LABEL of Dockerfile in [dir] only, package database is not used
+//   - Use to collect `Distro` and `Tag` of projects before New()
+func (t *TypeDocker) Scan(dir *string) *TypeDocker {
+	t.Base = new(basestruct.Base)
+	t.Initialized = true
+	t.MyType = "TypeDocker"
+	t.Dir = *dir
+	t.FilePath = path.Join(t.Dir, "Dockerfile")
+	if !file.IsRegularFile(t.FilePath) {
+		t.Err = errors.New(t.FilePath + " not found")
+	}
+	if t.Err == nil {
+		t.read().extract()
+	}
+	return t
+}
+
 func (t *TypeDocker) Updated() bool { return t.updated }
 
+// Bumped return true if FROM branch is changed by BumpBranch()
+func (t *TypeDocker) Bumped() bool { return t.BranchOld != "" }
+
+// UpdateAvailable return true if `VerNew` is newer and available on all target architectures,
+// for the package and all its pinned subpackages
+func (t *TypeDocker) UpdateAvailable() bool {
+	return t.VerNewer(t.VerNew, t.VerCurr) && len(t.HeldBack) == 0 && len(t.SubLag) == 0
+}
+
+// VerNewer return [v1] > [v2] using version comparison of the FROM distro
+//   - It logs debug output, like other database lookups, and ezlog is global,
+//     call it before building a log line, not inside one
+func (t *TypeDocker) VerNewer(v1, v2 string) bool {
+	if t.db == nil {
+		return VerNewer(v1, v2)
+	}
+	return t.db.VerNewer(v1, v2)
+}
+
+// Secfixes return security fix IDs(CVE) between `VerCurr` and `VerNew`
+//   - Looked up by origin package of `VerNew`, security database is by origin
+//   - Return nil if security database is not imported or has no fix
+func (t *TypeDocker) Secfixes() (ids []string) {
+	if t.db != nil && t.VerNewer(t.VerNew, t.VerCurr) {
+		origin := t.PkgDb
+		if t.PkgInfo != nil && t.PkgInfo.Origin != "" {
+			origin = t.PkgInfo.Origin
+		}
+		ids = t.db.Secfixes(origin, t.Branch, t.VerCurr, t.VerNew)
+	}
+	return ids
+}
+
+// BranchEol return end of life date of `Branch` if it is reached
+//   - Date of `RepoNew`, or first repository, is used if it ends before the branch, eg. Alpine community
+//   - Return empty string if not reached, not announced or release metadata is not imported
+func (t *TypeDocker) BranchEol() (eol string) {
+	if t.db != nil {
+		repo := t.RepoNew
+		if repo == "" && len(t.Repo) > 0 {
+			repo = t.Repo[0]
+		}
+		eol = t.db.BranchEol(t.Branch, repo)
+		if eol > time.Now().UTC().Format(time.DateOnly) {
+			eol = ""
+		}
+	}
+	return eol
+}
+
+// BranchNewer return newest stable branch if `Branch` is an older stable branch, eg. "v3.19" -> "v3.22"
+//   - Return empty string if `Branch` is not stable, eg. "edge", or newest stable branch is unknown, see branchLatest()
+func (t *TypeDocker) BranchNewer() (branch string) {
+	if t.db != nil && db.BranchIsStable(t.Branch) {
+		latest := t.branchLatest()
+		if latest != "" && db.VerNewer(latest[1:], t.Branch[1:]) {
+			branch = latest
+		}
+	}
+	return branch
+}
+
+// Return newest stable branch from release metadata, else newest stable of discovered branches
+//   - Return empty string if neither is available
+func (t *TypeDocker) branchLatest() (latest string) {
+	latest = t.db.BranchLatest()
+	if !db.BranchIsStable(latest) {
+		latest = ""
+		for _, branch := range t.db.Branches() {
+			if db.BranchIsStable(branch) && (latest == "" || db.VerNewer(branch[1:], latest[1:])) {
+				latest = branch
+			}
+		}
+	}
+	return latest
+}
+
+// BumpBranch rewrite FROM tag and "/<branch>/" repository URLs to BranchNewer() if [yes] is true,
+// then resolve package version again in the new branch
+//   - Only FROM of `Distro` is rewritten, other stages are untouched, digest is dropped
+//   - Branch is added to database if missing, see [db.Idb.BranchAdd], not if [offline]
+//   - Error if newest stable branch is unknown, neither release metadata nor discovered branches
+//   - Error if package is not available on all target architectures in the new branch,
+//     current version pinned in RUN line is usually not there
+func (t *TypeDocker) BumpBranch(yes, offline bool) *TypeDocker {
+	prefix := t.MyType + ".BumpBranch"
+	if yes && t.CheckErrInit(prefix) {
+		branch := t.BranchNewer()
+		if t.db != nil && db.BranchIsStable(t.Branch) && t.branchLatest() == "" {
+			t.Err = errors.New(t.Dir + "(" + t.Pkg + ") no release metadata, run db releases")
+			errs.Queue(prefix, t.Err)
+		}
+		if t.Err == nil && branch != "" {
+			tag := branch[1:] // "v3.22" -> "3.22"
+			if !offline {
+				t.db.BranchAdd([]string{branch})
+			}
+			ezlog.Debug().N(prefix).N(t.Pkg).M(t.Branch).M("->").M(branch).Out()
+			for index, line := range *t.Content {
+				words := strings.Split(line, " ")
+				if strings.ToLower(words[0]) == "from" {
+					for i, word := range words[1:] {
+						if word != "" && !strings.HasPrefix(word, "--") {
+							if db.ImageName(word) == t.Distro {
+								words[i+1] = t.Distro + ":" + tag
+							}
+							break
+						}
+					}
+					line = strings.Join(words, " ")
+				} else {
+					line = strings.ReplaceAll(line, "/"+t.Branch+"/", "/"+branch+"/")
+				}
+				(*t.Content)[index] = line
+			}
+			t.BranchOld = t.Branch
+			t.Branch = branch
+			t.Tag = tag
+			t.VerNew = ""
+			t.RepoNew = ""
+			t.PkgInfo = nil
+			t.VerArch = nil
+			t.HeldBack = nil
+			t.SubLag = nil
+			t.resolve().getVerNew()
+			if t.Err == nil && t.VerNew == "" {
+				msg := t.Dir + "(" + t.Pkg + ") not found in " + branch
+				if offline {
+					msg += ", branch is not added to database when offline"
+				}
+				t.Err = errors.New(msg)
+				errs.Queue(prefix, t.Err)
+			}
+			if t.Err == nil && (len(t.HeldBack) > 0 || len(t.SubLag) > 0) {
+				t.Err = errors.New(t.Dir + "(" + t.Pkg + ") " + t.VerNew + " held back in " + branch + ", not available on " + strings.Join(append(slices.Clone(t.HeldBack), t.SubLag...), ","))
+				errs.Queue(prefix, t.Err)
+			}
+		}
+	}
+	return t
+}
+
 // BuildTest if [yes] is true
 func (t *TypeDocker) BuildTest(yes bool) *TypeDocker {
 	if yes && t.updated {
@@ -149,17 +350,28 @@ func (t *TypeDocker) Dump(yes bool) *TypeDocker {
 }
 
 // Update [Content] buffer and write back
+//   - Branch bump of BumpBranch() is written even if version is not newer
 func (t *TypeDocker) Update() *TypeDocker {
 	prefix := t.MyType + ".Update"
 	if t.CheckErrInit(prefix) {
-		if VerNewer(t.VerNew, t.VerCurr) {
+		if t.UpdateAvailable() {
 			ezlog.Debug().N(prefix).N(t.Pkg).M(t.VerCurr).M("->").M(t.VerNew).Out()
-			pkgRunNew := t.Pkg + "=" + t.VerNew
+			// <Pkg=*> or <PkgDb=*>, see resolve()
+			pkgRun := pkgRunNew(t.PkgRun, t.Pkg, t.PkgVerSep, t.VerNew)
+			if pkgRun == t.PkgRun {
+				pkgRun = pkgRunNew(t.PkgRun, t.PkgDb, t.PkgVerSep, t.VerNew)
+			}
 			for index := range *t.Content {
 				(*t.Content)[index] = strings.ReplaceAll((*t.Content)[index], t.VerCurr, t.VerNew)
 				// above will miss package version in RUN line if LABEL has local patch level(-pXX)
-				(*t.Content)[index] = strings.ReplaceAll((*t.Content)[index], t.PkgRun, pkgRunNew)
+				(*t.Content)[index] = strings.ReplaceAll((*t.Content)[index], t.PkgRun, pkgRun)
+				// subpackages move together
+				for sub, subRun := range t.PkgSub {
+					(*t.Content)[index] = strings.ReplaceAll((*t.Content)[index], subRun, pkgRunNew(subRun, sub, t.PkgVerSep, t.VerNew))
+				}
 			}
+		}
+		if t.UpdateAvailable() || t.Bumped() {
 			t.write()
 			if t.Err == nil {
 				t.updated = true
@@ -171,11 +383,10 @@ func (t *TypeDocker) Update() *TypeDocker {
 
 // Extract information from `Content`
 //
-//   - FROM: `Distro`:`Branch`
+//   - FROM: `Distro`:`Tag`, `Distro` is image name without tag and digest
 //   - ARG: `Version`
 //   - LABEL: `Pkg`(package name)
 //   - LABEL: `Version`
-//   - RUN: <Pkg=*>
 func (t *TypeDocker) extract() *TypeDocker {
 	prefix := t.MyType + ".extract"
 	if t.CheckErrInit(prefix) {
@@ -195,15 +406,19 @@ func (t *TypeDocker) extract() *TypeDocker {
 					t.VerNew = ""
 				}
 			case "from":
-				ezlog.Debug().N(prefix).N(words[0]).M(words[1]).Out()
-				// just in case no branch
-				t.Distro = words[1]
-				// detect branch, eg. "alpine:edge" -> "edge"
-				tmp := strings.Split(words[1], ":")
-				if len(tmp) == 2 {
-					t.Distro = tmp[0]
-					t.Branch = tmp[1]
+				// skip flags, eg. "--platform=$BUILDPLATFORM"
+				image := ""
+				for _, word := range words[1:] {
+					if word != "" && !strings.HasPrefix(word, "--") {
+						image = word
+						break
+					}
 				}
+				ezlog.Debug().N(prefix).N(words[0]).M(image).Out()
+				// detect branch, eg. "alpine:edge" -> "edge", "registry.local:5000/base-alpine:3.20" -> "3.20"
+				t.Distro = db.ImageName(image)
+				t.Tag = db.ImageTag(image)
+				t.Branch = t.Tag
 			case "label":
 				ezlog.Debug().N(prefix).N(words[0]).M(words[1]).Out()
 				label := strings.Split(words[1], "=")
@@ -219,14 +434,6 @@ func (t *TypeDocker) extract() *TypeDocker {
 					t.Pkg = strings.ReplaceAll(label[1], "\"", "")
 				}
 			default:
-				// search for <Pkg=*>
-				for _, w := range words {
-					subStrArr := []string{t.Pkg + "="}
-					if str.ContainsAnySubStringsBool(w, &subStrArr, false) {
-						ezlog.Debug().N(prefix).N(words[0]).M(w).Out()
-						t.PkgRun = w
-					}
-				}
 				// detect branch testing
 				if strings.Contains(line, branchTesting) {
 					if !str.ArrayContains(&t.Repo, testing, false) {
@@ -239,22 +446,106 @@ func (t *TypeDocker) extract() *TypeDocker {
 	return t
 }
 
+// Resolve `Pkg` to real package name `PkgDb` and find <Pkg=*> in RUN line
+//   - `Pkg` can be a virtual package or command, eg. "cmd:sh" or "sh"
+//   - If <Pkg=*> is not in RUN line, search for <PkgDb=*>
+func (t *TypeDocker) resolve() *TypeDocker {
+	prefix := t.MyType + ".resolve"
+	if t.CheckErrInit(prefix) {
+		t.PkgRun = t.pkgRunSearch(t.Pkg)
+		t.PkgDb = t.db.PkgResolve(t.Pkg, t.Branch)
+		if t.PkgDb == "" {
+			t.PkgDb = t.Pkg
+		}
+		if t.PkgRun == "" && t.PkgDb != t.Pkg {
+			t.PkgRun = t.pkgRunSearch(t.PkgDb)
+		}
+		// pinned subpackages of the same origin
+		t.PkgSub = map[string]string{}
+		for _, sub := range t.db.OriginPkgs(t.PkgDb, t.Branch) {
+			if sub != t.PkgDb {
+				if w := t.pkgRunSearch(sub); w != "" {
+					t.PkgSub[sub] = w
+				}
+			}
+		}
+		ezlog.Debug().N(prefix).N(t.Pkg).M(t.PkgDb).Out()
+	}
+	return t
+}
+
+// Get newest version of each target architecture
+//   - `VerNew` is the newest of all target architectures
+//   - Target architectures with an older version are put in `HeldBack`,
+//     architectures without the package are not, eg. community package not built for armhf
+//   - Subpackages without `VerNew` on architectures of the package are put in `SubLag`
 func (t *TypeDocker) getVerNew() *TypeDocker {
 	prefix := t.MyType + ".getVerNew"
 
 	if t.CheckErrInit(prefix) {
-		// Check for new version
+		var (
+			archNew  string // architecture of `VerNew` and `RepoNew`
+			repoArch map[string]string
+		)
+		t.VerArch, repoArch = t.verArchGet(t.PkgDb)
+		for _, arch := range t.Arch {
+			if t.VerNewer(t.VerArch[arch], t.VerNew) {
+				t.VerNew = t.VerArch[arch]
+				t.RepoNew = repoArch[arch]
+				archNew = arch
+			}
+		}
+		for _, arch := range t.Arch {
+			if t.VerArch[arch] != "" && t.VerArch[arch] != t.VerNew {
+				t.HeldBack = append(t.HeldBack, arch)
+			}
+		}
+		for sub := range t.PkgSub {
+			verArch, _ := t.verArchGet(sub)
+			for _, arch := range t.Arch {
+				if t.VerArch[arch] != "" && verArch[arch] != t.VerNew {
+					t.SubLag = append(t.SubLag, sub)
+					break
+				}
+			}
+		}
+		slices.Sort(t.SubLag)
+		ezlog.Debug().N(prefix).N(t.Pkg).M(t.VerNew).N("HeldBack").M(t.HeldBack).N("SubLag").M(t.SubLag).Out()
+		if t.VerNew != "" {
+			t.PkgInfo = t.db.Info(t.PkgDb, t.Branch, t.RepoNew, archNew, t.VerNew)
+		}
+	}
+	return t
+}
+
+// Return newest version of [pkg] and its repository, per target architecture
+func (t *TypeDocker) verArchGet(pkg string) (verArch, repoArch map[string]string) {
+	prefix := t.MyType + ".verArchGet"
+	verArch = map[string]string{}
+	repoArch = map[string]string{}
+	for _, arch := range t.Arch {
 		for _, b := range t.Repo {
-			verNew := *t.db.VerGet(t.Pkg, t.Branch, b)
+			verNew := *t.db.VerGet(pkg, t.Branch, b, arch)
 			if t.db.Err() == nil {
-				if verNew > t.VerNew {
-					t.VerNew = verNew
-					ezlog.Debug().N(prefix).N(t.Branch + "/" + b).N(t.Pkg).M(verNew).M(">").M(t.VerCurr).Out()
+				if t.VerNewer(verNew, verArch[arch]) {
+					verArch[arch] = verNew
+					repoArch[arch] = b
+					ezlog.Debug().N(prefix).N(t.Branch + "/" + b + "/" + arch).N(pkg).M(verNew).M(">").M(t.VerCurr).Out()
 				}
 			}
 		}
 	}
-	return t
+	return verArch, repoArch
+}
+
+// Return the <pkg=*> word in `Content`, empty string if not found
+func (t *TypeDocker) pkgRunSearch(pkg string) (pkgRun string) {
+	for _, line := range *t.Content {
+		if w := pkgRunFind(strings.Split(line, " "), pkg, t.PkgVerSep); w != "" {
+			pkgRun = w
+		}
+	}
+	return pkgRun
 }
 
 // Read Dockerfile into `Content`
@@ -283,3 +574,31 @@ func (t *TypeDocker) write() *TypeDocker {
 	}
 	return t
 }
+
+// Return the <pkg[sep]*> word in [words], empty string if not found
+//   - <pkg[sep]*> must not be preceded by a package name character, eg. "libfoo=" is not "foo="
+//   - If [sep] is "-", version must start with a digit, eg. "foo-devel-1.0" is not "foo-1.0"
+func pkgRunFind(words []string, pkg, sep string) (pkgRun string) {
+	subStr := strings.ToLower(pkg + sep)
+	for _, w := range words {
+		i := strings.Index(strings.ToLower(w), subStr)
+		if i < 0 || (i > 0 && strings.ContainsRune(pkgNameChars, rune(w[i-1]))) {
+			continue
+		}
+		if j := i + len(subStr); sep == "-" && (j >= len(w) || w[j] < '0' || w[j] > '9') {
+			continue
+		}
+		pkgRun = w
+	}
+	return pkgRun
+}
+
+// Return [pkgRun] with version replaced by [ver], eg. "foo=1.0", "foo", "=", "1.1" -> "foo=1.1"
+//   - Anything before <pkg> is kept
+func pkgRunNew(pkgRun, pkg, sep, ver string) string {
+	i := strings.Index(strings.ToLower(pkgRun), strings.ToLower(pkg+sep))
+	if i < 0 {
+		return pkgRun
+	}
+	return pkgRun[:i+len(pkg)] + sep + ver
+}
@@ -24,22 +24,52 @@ THE SOFTWARE.
 
 package lib
 
+import "github.com/J-Siu/go-auto-docker/db"
+
 // Holding all flags from command line
 type TypeFlag struct {
-	Debug    bool // Enable debug output
-	UpdateDb bool // Update package database
+	Debug    bool   // Enable debug output
+	Distro   string // Package database of db commands
+	Offline  bool   // Do not download, warn and exit non-zero if database is stale
+	UpdateDb bool   // Update package database
 	Verbose  bool
 }
 
+// Holding all flags for check
+type TypeFlagCheck struct {
+	Security bool // List security fixes(CVE) of available update
+}
+
 // Holding all flags for update
 type TypeFlagUpdate struct {
-	Commit    bool // Apply git commit. Only work with -save
-	BuildTest bool // Do not perform docker build
-	Save      bool // Write back to project folder
-	Tag       bool // Apply git tag. Only work with -commit
+	BumpBranch bool // Move FROM and repository URLs to newest stable branch
+	Commit     bool // Apply git commit. Only work with -save
+	BuildTest  bool // Do not perform docker build
+	Save       bool // Write back to project folder
+	Tag        bool // Apply git tag. Only work with -commit
 }
 
-// Holding all flags for db
+// Holding all flags for db search
 type TypeFlagDbSearch struct {
-	Exact bool // Search exact word
+	db.TypeDbSearchOption        // Branch, repo, arch filters and match mode
+	Format                string // table, json
+	Provides              bool   // Search by what packages provide
+	Text                  bool   // Full-text search in name, description and URL
+}
+
+// Holding all flags for db deps/rdeps
+type TypeFlagDbDeps struct {
+	Arch      string // Architecture
+	Branch    string // Branch
+	Recursive bool   // Walk whole dependency tree, deps only
+	Repo      string // Repository, empty for all
+}
+
+// Holding all flags for db export
+type TypeFlagDbExport struct {
+	Arch   string // Architecture, empty for all
+	Branch string // Branch, empty for all
+	Format string // json, csv, ndjson
+	Output string // Output file, stdout if empty
+	Repo   string // Repository, empty for all
 }
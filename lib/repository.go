@@ -108,7 +108,10 @@ func (t *TypeRepository) CopyCacheToSrc() *TypeRepository {
 	return t
 }
 
-func (t *TypeRepository) Commit(msg string, tag bool, cache bool) *TypeRepository {
+// Commit all changes with message [msg], tag with [msg] if [tag]
+//   - [body] is appended to commit message after an empty line, if not empty
+//   - Commit in cache copy if [cache], otherwise in source
+func (t *TypeRepository) Commit(msg, body string, tag bool, cache bool) *TypeRepository {
 	prefix := t.MyType + ".Commit"
 	ezlog.Debug().N(prefix).TxtStart().Out()
 	if t.Err != nil {
@@ -158,7 +161,11 @@ func (t *TypeRepository) Commit(msg string, tag bool, cache bool) *TypeRepositor
 	}
 	// Worktree commit
 	if t.Err == nil {
-		commit, t.Err = gitWorktree.Commit(msg, &commitOptions)
+		commitMsg := msg
+		if body != "" {
+			commitMsg += "\n\n" + body
+		}
+		commit, t.Err = gitWorktree.Commit(commitMsg, &commitOptions)
 		// commit, p.Err = gitWorktree.Commit(msg, nil)
 		ezlog.Debug().N(prefix).M("worktree committed").Out()
 	}